@@ -0,0 +1,172 @@
+// Command confetti documents the configs loaded by the confetti package.
+//
+// Usage:
+//
+//	confetti envdoc [-prefix PREFIX] [-format markdown|text|dotenv] [-o FILE] PACKAGE.TYPE
+//
+// The envdoc subcommand lists the env vars WithEnv(PREFIX) consults for the config struct TYPE,
+// declared in PACKAGE (an import path or a relative one, e.g. ./internal/config.Config), with
+// their Go type, default value, whether they are required and their description (see the `desc`
+// struct tag), as a Markdown table, a plain text table or a sample .env file.
+//
+// It must run from within the module declaring the config, which must not be in a main package:
+// it generates a program calling confetti.WriteEnvDoc on the config and runs it with "go run".
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexaandru/confetti"
+)
+
+const usage = `usage: confetti envdoc [-prefix PREFIX] [-format markdown|text|dotenv] [-o FILE] PACKAGE.TYPE`
+
+// envDocProgram is the program printing the env doc of the config type, given the import path
+// of its package, its name, the prefix and the format.
+const envDocProgram = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexaandru/confetti"
+	config %q
+)
+
+func main() {
+	if err := confetti.WriteEnvDoc(os.Stdout, config.%s{}, %q, %q); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "envdoc" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := envDoc(os.Args[2:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "confetti envdoc:", err)
+		os.Exit(1)
+	}
+}
+
+// envDoc runs the envdoc subcommand with the given args, writing the doc to stdout
+// (unless the -o flag is given) and the errors of the generated program to stderr.
+func envDoc(args []string, stdout, stderr io.Writer) (err error) {
+	fs := flag.NewFlagSet("envdoc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, usage) //nolint:errcheck // ok
+		fs.PrintDefaults()
+	}
+
+	prefix := fs.String("prefix", "", "the env var prefix, as given to confetti.WithEnv")
+	format := fs.String("format", string(confetti.EnvDocMarkdown), "the output format: markdown, text or dotenv")
+	output := fs.String("o", "", "the output file (default is stdout)")
+
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expecting exactly one PACKAGE.TYPE argument")
+	}
+
+	switch confetti.EnvDocFormat(*format) {
+	case confetti.EnvDocMarkdown, confetti.EnvDocText, confetti.EnvDocDotEnv:
+	default:
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	pkg, typ, err := splitType(fs.Arg(0))
+	if err != nil {
+		return
+	}
+
+	importPath, err := goList(pkg)
+	if err != nil {
+		return
+	}
+
+	if *output != "" {
+		f, createErr := os.Create(*output)
+		if createErr != nil {
+			return createErr
+		}
+
+		defer func() {
+			err = errors.Join(err, f.Close())
+		}()
+
+		stdout = f
+	}
+
+	return runProgram(fmt.Sprintf(envDocProgram, importPath, typ, *prefix, *format), stdout, stderr)
+}
+
+// splitType splits the PACKAGE.TYPE argument s into the package and the type name.
+func splitType(s string) (pkg, typ string, err error) {
+	i := strings.LastIndex(s, ".")
+	if i <= strings.LastIndex(s, "/") {
+		return "", "", fmt.Errorf("invalid argument %q, expecting PACKAGE.TYPE", s)
+	}
+
+	if pkg, typ = s[:i], s[i+1:]; !token.IsIdentifier(typ) || !token.IsExported(typ) {
+		return "", "", fmt.Errorf("invalid type name %q, expecting an exported one", typ)
+	}
+
+	return
+}
+
+// goList returns the import path of the package pkg, as resolved by "go list".
+func goList(pkg string) (string, error) {
+	out, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", pkg).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("go list %s: %s", pkg, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runProgram runs the Go program src with "go run", from a temporary directory within
+// the current one, so that it builds within the current module.
+func runProgram(src string, stdout, stderr io.Writer) (err error) {
+	dir, err := os.MkdirTemp(".", "confetti-envdoc-")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		err = errors.Join(err, os.RemoveAll(dir))
+	}()
+
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o600); err != nil {
+		return
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("running the generated program: %w", err)
+	}
+
+	return
+}
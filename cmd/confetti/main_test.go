@@ -0,0 +1,28 @@
+//nolint:testpackage // ok
+package main
+
+import "testing"
+
+func TestSplitType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in, pkg, typ string
+		ok           bool
+	}{
+		{"./internal/config.Config", "./internal/config", "Config", true},
+		{"github.com/acme/app/config.AppConfig", "github.com/acme/app/config", "AppConfig", true},
+		{"..Config", ".", "Config", true},
+		{"./internal/config", "", "", false},
+		{"github.com/acme/app", "", "", false},
+		{"./config.config", "", "", false},
+		{"./config.", "", "", false},
+	}
+
+	for _, c := range cases {
+		pkg, typ, err := splitType(c.in)
+		if (err == nil) != c.ok || pkg != c.pkg || typ != c.typ {
+			t.Errorf("splitType(%q) = %q, %q, %v; want %q, %q, ok=%v", c.in, pkg, typ, err, c.pkg, c.typ, c.ok)
+		}
+	}
+}
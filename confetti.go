@@ -2,22 +2,45 @@ package confetti
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
-	"os"
+	"maps"
 	"reflect"
+
+	"github.com/alexaandru/confetti/internal/remap"
 )
 
 // Loader is the interface implemented by all config loaders (env, SSM, JSON).
-// You can implement your own Loader to support custom sources.
+// You can implement your own Loader to support custom sources: it receives the
+// target config (always a pointer to a struct) and the options of the current Load call.
+//
+// A Loader may optionally implement fmt.Stringer to describe its source
+// (e.g. "env:MYAPP"), which is then exposed to it via Options.Source.
 type Loader interface {
-	Load(targetConfig any, ownConfig *confetti) error
+	Load(targetConfig any, opts *Options) error
 }
 
-type confetti struct {
-	mockedSSM    SSMAPI
-	errOnUnknown bool
+// Options holds the state shared by all loaders during a single Load call.
+type Options struct {
+	// Context is the context of the current Load call. Loaders doing I/O should honour it.
+	// It is never nil when set by Load or LoadContext.
+	Context context.Context //nolint:containedctx // it only lives for the duration of a Load call.
+	// Source is the name of the loader currently running (e.g. "env:MYAPP", "json:config.json").
+	Source string
+	// ErrOnUnknown is true when WithErrOnUnknown was passed to Load.
+	ErrOnUnknown bool
+
+	mockedSSM            SSMAPI
+	mockedSecretsManager SecretsManagerAPI
+	decoders             map[reflect.Type]DecodeFunc
+	touched              map[string]string
+	envPrefixes          []string
+	provenance           *Provenance
+	merge                bool
+	interpolation        *optsInterpolationLoader
 }
 
 // Load applies one or more loader functions to populate the given config which MUST be
@@ -25,10 +48,17 @@ type confetti struct {
 //
 // The first argument must be a pointer to a struct. Each loader (such as WithEnv, WithSSM, WithJSON)
 // is applied in order, with later loaders overriding values from earlier ones.
+// Before any of them, zero valued fields are populated from their `default` struct tags, if any,
+// and so are, after all of them, those of the pointers to structs which the loaders allocated.
+// With WithInterpolation, the references in string values (e.g. ${NAME}) are resolved after all of them.
+// After all of them, fields tagged `required:"true"` (or `env:",required"`) which are still zero
+// and were never set by any loader are reported, all at once, in an ErrMissingRequired error.
+// Finally, the config is validated (see Validator and the `validate` struct tag) and any
+// failures are reported, all at once, in an ErrInvalidConfig error.
 //
-// You can optionally pass options.
-// Currently, the only supported option is WithErrOnUnknown, which controls whether to return an error
-// when unknown fields are present in the source but not defined in the target config.
+// You can optionally pass options, built by the With* option constructors (such as WithErrOnUnknown,
+// WithDecoder, WithMerge, WithProvenance or WithInterpolation, see their docs), which are applied
+// before any loader regardless of their position.
 //
 // Returns an error if the config pointer is nil, not a struct, or if any loader fails.
 //
@@ -38,6 +68,43 @@ type confetti struct {
 //	err := confetti.Load(&cfg, confetti.WithJSON("./config.json"), confetti.WithEnv("MYAPP"))
 //	if err != nil { panic(err) }
 func Load(cfg any, ld Loader, opts ...Loader) (err error) {
+	return LoadContext(context.Background(), cfg, ld, opts...)
+}
+
+// LoadContext is like Load but threads ctx through every loader, so that remote
+// loaders (such as WithSSM) honour its deadline and cancellation.
+//
+// If ctx is done before or while a loader runs, the returned error wraps ctx.Err()
+// and is prefixed with the name of that loader (e.g. "ssm:/my/param").
+func LoadContext(ctx context.Context, cfg any, ld Loader, opts ...Loader) (err error) {
+	return load(ctx, cfg, append([]Loader{ld}, opts...))
+}
+
+// LoadAs is like Load, but returns the config, of type T, instead of populating one.
+// Unlike with Load, passing something else than a pointer is not possible, although T
+// must still be a struct type.
+//
+// Example usage:
+//
+//	cfg, err := confetti.LoadAs[MyConfig](confetti.WithJSON("./config.json"), confetti.WithEnv("MYAPP"))
+func LoadAs[T any](loaders ...Loader) (cfg T, err error) {
+	err = load(context.Background(), &cfg, loaders)
+	return
+}
+
+// MustLoad is like LoadAs, but panics if loading the config fails.
+// It is meant for loading configs at startup, e.g. in package level variable declarations.
+func MustLoad[T any](loaders ...Loader) T {
+	cfg, err := LoadAs[T](loaders...)
+	if err != nil {
+		panic(fmt.Sprintf("confetti: loading %T: %v", cfg, err))
+	}
+
+	return cfg
+}
+
+// load implements LoadContext, applying the given loaders in order.
+func load(ctx context.Context, cfg any, loaders []Loader) (err error) {
 	if cfg == nil {
 		return errors.New("config pointer cannot be nil")
 	}
@@ -47,31 +114,75 @@ func Load(cfg any, ld Loader, opts ...Loader) (err error) {
 		return fmt.Errorf("config must be a pointer to a struct (got %T)", cfg)
 	}
 
-	c, optx, ldx := confetti{}, []Loader{}, []Loader{}
+	o, optx, ldx := Options{Context: ctx}, []Loader{}, []Loader{defaultsLoader{}}
+
+	defer func() {
+		if o.provenance != nil {
+			*o.provenance = maps.Clone(o.touched)
+		}
+	}()
 
 	// Separate loaders into "opts setters" and actual loaders.
-	for _, ld := range append([]Loader{ld}, opts...) {
+	for _, ld := range loaders {
 		switch ld.(type) {
-		case optsLoader, optsMockedSSMLoader:
+		case optsLoader, optsMockedSSMLoader, optsMockedSecretsManagerLoader, optsDecoderLoader, optsProvenanceLoader,
+			optsWatchLoader, optsMergeLoader, optsInterpolationLoader:
 			optx = append(optx, ld)
 		default:
 			ldx = append(ldx, ld)
 		}
 	}
 
-	// Then ensure that setters are applied first.
-	for _, ld := range append(optx, ldx...) {
-		if err = ld.Load(cfg, &c); err != nil {
+	// Then ensure that setters are applied first. Neither they nor the defaults are sources
+	// the caller asked for, so a done context is reported for the first actual loader instead.
+	for i, ld := range append(optx, ldx...) {
+		o.Source = sourceOf(ld)
+
+		if i > len(optx) && ctx.Err() != nil {
+			return fmt.Errorf("%s: %w", o.Source, ctx.Err())
+		}
+
+		if err = ld.Load(cfg, &o); err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("%s: %w", o.Source, err)
+			}
+
 			return
 		}
 	}
 
-	return
+	// Pointers to structs allocated by the loaders did not get their defaults yet.
+	o.Source = sourceOf(defaultsLoader{})
+	if err = (defaultsLoader{}).Load(cfg, &o); err != nil {
+		return
+	}
+
+	if o.interpolation != nil {
+		if err = interpolate(v.Elem(), &o); err != nil {
+			return
+		}
+	}
+
+	if err = checkRequired(v.Elem(), &o); err != nil {
+		return
+	}
+
+	return validate(v.Elem())
+}
+
+// sourceOf returns the name of the source a loader reads from.
+func sourceOf(ld Loader) string {
+	if s, ok := ld.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", ld)
 }
 
 // WithErrOnUnknown sets whether to return an error if is present in the source but
 // not defined in the config struct.
-// NOTE: It currently only applies to json and ssm loaders.
+// NOTE: It applies to the JSON based loaders (e.g. WithJSON, WithSSM) and, for the env vars
+// having their prefix, to the env loaders (see UnknownEnvError).
 func WithErrOnUnknown() optsLoader {
 	return optsLoader{errOnUnknown: true}
 }
@@ -81,19 +192,73 @@ func WithMockedSSM(client SSMAPI) optsMockedSSMLoader {
 	return optsMockedSSMLoader{client: client}
 }
 
+// WithMockedSecretsManager returns a loader that uses a mocked Secrets Manager client for testing.
+func WithMockedSecretsManager(client SecretsManagerAPI) optsMockedSecretsManagerLoader {
+	return optsMockedSecretsManagerLoader{client: client}
+}
+
+// WithDecoder registers a decoder for the values of type t, for the string based loaders
+// (e.g. WithEnv, WithFlags and `default` tags). It takes precedence over the builtin conversions and over
+// the UnmarshalText or UnmarshalJSON methods of t, which are otherwise used, if present.
+// The value returned by fn must be assignable to t.
+//
+// Usage:
+//
+//	confetti.WithDecoder(reflect.TypeFor[big.Int](), func(s string) (any, error) { ... })
+func WithDecoder(t reflect.Type, fn DecodeFunc) optsDecoderLoader {
+	return optsDecoderLoader{t: t, fn: fn}
+}
+
 // WithEnv returns a loader that populates struct fields from environment variables.
 //
 // The prefix is prepended to each field name (in UPPER_SNAKE_CASE) to form the env var name.
 //
-// The optional separator argument sets the delimiter for slice fields (default is ",").
-// Supports primitive types and slices of primitives (string, int, uint, float, bool).
+// The optional separator argument sets the delimiter for slice fields and map pairs (default is ",")
+// and the optional kvSeparator (second) argument sets the delimiter of map keys and values (default is "=").
+// Supports primitive types and slices of primitives (string, int, uint, float, bool),
+// as well as pointers to them and to nested structs, which are only allocated if
+// (any of) their env var(s) is set. Types implementing encoding.TextUnmarshaler or
+// json.Unmarshaler (e.g. net.IP, time.Time, slog.Level), url.URL and types registered
+// via WithDecoder are supported too, including as slice elements and map keys/values.
+//
+// Maps of primitives are loaded from pairs (e.g. MYAPP_LABELS="team=core,tier=1") and/or from
+// one env var per key (e.g. MYAPP_LABELS_TEAM=core), the latter taking precedence. Keys taken
+// from env var names are lowercased.
 func WithEnv(prefix string, opts ...string) envLoader {
-	separator := DefaultSeparator
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}
 	if len(opts) > 0 {
-		separator = opts[0]
+		dec.separator = opts[0]
 	}
 
-	return envLoader{prefix: prefix, separator: separator}
+	if len(opts) > 1 {
+		dec.kvSeparator = opts[1]
+	}
+
+	return envLoader{prefix: prefix, decoder: dec}
+}
+
+// WithDotEnv returns a loader that populates struct fields from the env vars defined in
+// the .env file at path, exactly as WithEnv(prefix) would from the process env, which is
+// neither read (except for ${VAR} references) nor modified.
+//
+// The file holds one NAME=value pair per line, optionally preceded by "export", with # comments.
+// Values can be single quoted (taken literally) or double quoted (supporting escapes and spanning
+// several lines); except for single quoted ones, they can reference other vars as ${VAR}.
+func WithDotEnv(path, prefix string) dotEnvLoader {
+	return dotEnvLoader{envLoader: WithEnv(prefix), path: path}
+}
+
+// WithFlags returns a loader that registers a flag in fs for each config field, then parses args
+// and sets the fields whose flags were given, leaving the others untouched. It is meant to be
+// the last loader, so that flags override everything else. If fs is nil, flag.CommandLine is
+// used and if args is nil, os.Args[1:] is.
+//
+// Flag names are the kebab-case field paths (e.g. -nested-deep-foo for Nested.Deep.Foo) and can
+// be overridden with the `flag` struct tag (which, on a nested struct, sets the prefix of its
+// fields' flags), while `flag:"-"` skips a field. The `usage` struct tag sets the help text.
+// Values are converted like env vars are (see WithEnv); map flags can be repeated to add entries.
+func WithFlags(fs *flag.FlagSet, args []string) flagsLoader {
+	return flagsLoader{fs: fs, args: args}
 }
 
 // WithSSM returns a loader that loads the config struct from an AWS SSM parameter.
@@ -117,29 +282,122 @@ func WithSSM(key string, opts ...string) ssmLoader {
 	return ssmLoader{key: key, awsRegion: awsRegion, profile: profile}
 }
 
+// WithSSMPath returns a loader that loads the config struct from all the AWS SSM parameters
+// under the given path (recursively, decrypting SecureString ones), one parameter per field.
+//
+// Parameter names are mapped to fields the same way env var names are: each path segment
+// (relative to path) is matched against the field names in UPPER_SNAKE_CASE, with dashes
+// treated as underscores, so that "/myapp/prod/db/max-retries" sets DB.MaxRetries. Segments
+// can be overridden per field via the `ssm` struct tag (e.g. `ssm:"pass"`, absolute names being
+// reserved for WithSSMRefs). Values are converted
+// with the same rules as env vars, StringList parameters being split into slices.
+//
+// The optional region and profile arguments override the default AWS region/profile.
+//
+// Usage:
+//
+//	confetti.WithSSMPath("/myapp/prod", "us-west-2", "myprofile")
+func WithSSMPath(path string, opts ...string) ssmPathLoader {
+	awsRegion, profile := DefaultAWSRegion, ""
+	if len(opts) > 0 {
+		awsRegion = opts[0]
+	}
+
+	if len(opts) > 1 {
+		profile = opts[1]
+	}
+
+	return ssmPathLoader{path: path, awsRegion: awsRegion, profile: profile}
+}
+
+// WithSSMRefs returns a loader that loads the fields which reference an AWS SSM parameter
+// via their `ssm` struct tag (e.g. `ssm:"/prod/db/password"`, absolute names only), fetching
+// (and decrypting) all of them in batched calls. Values are converted with the same rules as
+// env vars and all the parameters that could not be found are reported at once.
+//
+// The optional region and profile arguments override the default AWS region/profile.
+//
+// Usage:
+//
+//	confetti.WithSSMRefs("us-west-2", "myprofile")
+func WithSSMRefs(opts ...string) ssmRefsLoader {
+	awsRegion, profile := DefaultAWSRegion, ""
+	if len(opts) > 0 {
+		awsRegion = opts[0]
+	}
+
+	if len(opts) > 1 {
+		profile = opts[1]
+	}
+
+	return ssmRefsLoader{awsRegion: awsRegion, profile: profile}
+}
+
+// WithSecretsManager returns a loader that loads the config struct from an AWS Secrets Manager secret.
+//
+// The secretID is the secret name or ARN and its value (string or binary) must be a JSON string matching
+// the config struct. The optional region, profile and version stage arguments override the default AWS
+// region/profile and version stage (AWSCURRENT), e.g. to load the AWSPREVIOUS version during a rotation.
+//
+// Usage:
+//
+//	confetti.WithSecretsManager("prod/myapp", "us-west-2", "myprofile", "AWSPREVIOUS")
+func WithSecretsManager(secretID string, opts ...string) secretsManagerLoader {
+	awsRegion, profile, versionStage := DefaultAWSRegion, "", ""
+	if len(opts) > 0 {
+		awsRegion = opts[0]
+	}
+
+	if len(opts) > 1 {
+		profile = opts[1]
+	}
+
+	if len(opts) > 2 {
+		versionStage = opts[2]
+	}
+
+	return secretsManagerLoader{secretID: secretID, awsRegion: awsRegion, profile: profile, versionStage: versionStage}
+}
+
 // WithJSON returns a loader that loads the config struct from a JSON source,
 // which can be: a file path (string), []byte, io.ReadSeeker or io.Reader.
+// Durations can be given as strings (e.g. "5s") or nanoseconds.
 func WithJSON(src any) jsonLoader {
 	switch v := src.(type) {
 	case string:
-		f, err := os.Open(v) //nolint:gosec // this is the whole point of the library.
-		if err != nil {
-			return jsonLoader{err: err}
-		}
-
-		return jsonLoader{r: f, c: f}
+		return jsonLoader{src: v, path: v}
 	case []byte:
-		return jsonLoader{r: bytes.NewReader(v)}
+		return jsonLoader{src: "[]byte", r: bytes.NewReader(v)}
 	case io.ReadSeeker:
-		return jsonLoader{r: v}
+		return jsonLoader{src: "io.ReadSeeker", r: v}
 	case io.Reader:
 		b, err := io.ReadAll(v)
 		if err != nil {
-			return jsonLoader{err: err}
+			return jsonLoader{src: "io.Reader", err: err}
 		}
 
-		return jsonLoader{r: bytes.NewReader(b)}
+		return jsonLoader{src: "io.Reader", r: bytes.NewReader(b)}
 	default:
 		return jsonLoader{err: fmt.Errorf("unsupported type for WithJSON: %T", src)}
 	}
 }
+
+// WithJSONPatch returns a loader that applies a JSON Patch (RFC 6902) document, read from src
+// (see WithJSON for the supported sources), to the config as set by the previous loaders, e.g.:
+//
+//	[{"op": "replace", "path": "/Port", "value": 9090}, {"op": "remove", "path": "/Labels/tier"}]
+//
+// The patch applies to the JSON representation of the config (including the fields omitted as
+// empty), but only the fields it changes are set (removed ones are reset to their zero value),
+// so that the others, including secrets (see Secret), are preserved. If any operation fails,
+// the error wraps ErrPatchFailed.
+func WithJSONPatch(src any) patchLoader {
+	return patchLoader{src: remap.NewSource(src, "WithJSONPatch")}
+}
+
+// WithMergePatch is like WithJSONPatch, for JSON Merge Patch (RFC 7386) documents, e.g.:
+//
+//	{"Port": 9090, "Labels": {"tier": null}}
+func WithMergePatch(src any) patchLoader {
+	return patchLoader{src: remap.NewSource(src, "WithMergePatch"), merge: true}
+}
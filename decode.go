@@ -0,0 +1,297 @@
+package confetti
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decoder converts strings (e.g. env var values, default tags) into typed values.
+type decoder struct {
+	// custom holds the decoders registered via WithDecoder.
+	custom map[reflect.Type]DecodeFunc
+	// separator delimits the items of slices and the key/value pairs of maps.
+	separator string
+	// kvSeparator delimits the key from the value, in map pairs.
+	kvSeparator string
+}
+
+// DecodeFunc decodes a string into a value of the type it was registered for (see WithDecoder).
+type DecodeFunc func(string) (any, error)
+
+// withCustom returns a copy of d using the custom decoders registered in opts, if any.
+func (d decoder) withCustom(opts *Options) decoder {
+	if opts != nil {
+		d.custom = opts.decoders
+	}
+
+	return d
+}
+
+// nested reports whether t is a struct (or a pointer to one) whose fields are to be
+// loaded one by one, as opposed to a type which is decoded as a whole (e.g. time.Time).
+func (d decoder) nested(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && !d.decodable(t)
+}
+
+// decodable reports whether t is decoded as a whole, either by a custom decoder
+// or by its own UnmarshalText/UnmarshalJSON method, rather than based on its kind.
+func (d decoder) decodable(t reflect.Type) bool {
+	if _, ok := d.custom[t]; ok || t == reflect.TypeFor[url.URL]() {
+		return true
+	}
+
+	for _, it := range []reflect.Type{reflect.TypeFor[encoding.TextUnmarshaler](), reflect.TypeFor[json.Unmarshaler]()} {
+		if t.Implements(it) || reflect.PointerTo(t).Implements(it) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canDecode reports whether setElem can set values of type t.
+func (d decoder) canDecode(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr && !d.decodable(t) {
+		t = t.Elem()
+	}
+
+	return d.decodable(t) || isScalar(t.Kind())
+}
+
+// setValue sets v from its string representation val, splitting it by separator for slices
+// and maps (whose items are key/value pairs delimited by kvSeparator). Map entries are added
+// to the existing ones, if any. Pointers are allocated and their element set. The name is
+// used as the prefix of any returned error. Unsupported kinds are ignored.
+//
+// Types having a custom decoder (see WithDecoder) or implementing encoding.TextUnmarshaler
+// (or, failing that, json.Unmarshaler) are decoded as a whole, by those.
+func (d decoder) setValue(v reflect.Value, val, name string) error {
+	if d.decodable(v.Type()) {
+		return d.decode(v, val, name)
+	}
+
+	switch v.Kind() { //nolint:exhaustive // ok
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		if err := d.setValue(p.Elem(), val, name); err != nil {
+			return err
+		}
+
+		v.Set(p)
+	case reflect.Map:
+		if strings.TrimSpace(val) == "" {
+			break
+		}
+
+		for _, pair := range strings.Split(val, d.separator) {
+			key, elem, ok := strings.Cut(pair, d.kvSeparator)
+			if !ok {
+				return fmt.Errorf("%s: invalid key/value pair %q", name, pair)
+			}
+
+			key = strings.TrimSpace(key)
+			if err := d.setMapEntry(v, key, strings.TrimSpace(elem), fmt.Sprintf("%s[%s]", name, key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if !d.canDecode(v.Type().Elem()) {
+			return fmt.Errorf("%s: unsupported slice element type %s", name, v.Type().Elem().Kind())
+		}
+
+		parts := strings.Split(val, d.separator)
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+
+		for j, part := range parts {
+			if err := d.setElem(slice.Index(j), strings.TrimSpace(part), fmt.Sprintf("%s[%d]", name, j)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+	default:
+		return setScalar(v, val, name)
+	}
+
+	return nil
+}
+
+// setMapEntry sets the key of map v (allocating it, if nil) to elem.
+func (d decoder) setMapEntry(v reflect.Value, key, elem, name string) error {
+	t := v.Type()
+	if !d.canDecode(t.Key()) || !d.canDecode(t.Elem()) {
+		return fmt.Errorf("%s: unsupported map type %s", name, t)
+	}
+
+	k, e := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+	if err := d.setElem(k, key, name); err != nil {
+		return err
+	}
+
+	if err := d.setElem(e, elem, name); err != nil {
+		return err
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(t))
+	}
+
+	v.SetMapIndex(k, e)
+
+	return nil
+}
+
+// setElem sets v, a slice element or map key/value, from its string representation val.
+func (d decoder) setElem(v reflect.Value, val, name string) error {
+	if d.decodable(v.Type()) {
+		return d.decode(v, val, name)
+	}
+
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := d.setElem(p.Elem(), val, name); err != nil {
+			return err
+		}
+
+		v.Set(p)
+
+		return nil
+	}
+
+	return setScalar(v, val, name)
+}
+
+// decode sets v from val using, in order, the custom decoder registered for its type,
+// url.Parse (for url.URL), its UnmarshalText method or its UnmarshalJSON method.
+// The latter is given val as is, if it is valid JSON, or as a JSON string otherwise.
+func (d decoder) decode(v reflect.Value, val, name string) (err error) {
+	t := v.Type()
+
+	if fn, ok := d.custom[t]; ok {
+		var x any
+
+		if x, err = fn(val); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		xv := reflect.ValueOf(x)
+		if !xv.IsValid() || !xv.Type().AssignableTo(t) {
+			return fmt.Errorf("%s: decoder for %s returned %T", name, t, x)
+		}
+
+		v.Set(xv)
+
+		return
+	}
+
+	if t == reflect.TypeFor[url.URL]() {
+		var u *url.URL
+
+		if u, err = url.Parse(val); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		v.Set(reflect.ValueOf(*u))
+
+		return
+	}
+
+	// Pointer types implementing the interfaces via a pointer receiver are allocated.
+	if t.Kind() == reflect.Ptr && v.IsNil() {
+		v.Set(reflect.New(t.Elem()))
+	}
+
+	target := v
+	if t.Kind() != reflect.Ptr {
+		target = v.Addr()
+	}
+
+	switch u := target.Interface().(type) {
+	case encoding.TextUnmarshaler:
+		err = u.UnmarshalText([]byte(val))
+	case json.Unmarshaler:
+		data := []byte(val)
+		if !json.Valid(data) {
+			data, _ = json.Marshal(val) //nolint:errchkjson // marshaling a string cannot fail.
+		}
+
+		err = u.UnmarshalJSON(data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return
+}
+
+// setScalar sets a non-slice v from its string representation val.
+func setScalar(v reflect.Value, val, name string) error {
+	switch v.Kind() { //nolint:exhaustive // ok
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeFor[time.Duration]() {
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			v.SetInt(int64(d))
+
+			break
+		}
+
+		iv, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		v.SetInt(iv)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uv, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		v.SetUint(uv)
+	case reflect.Bool:
+		bv, err := parseBool(val)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		v.SetBool(bv)
+	case reflect.Float32, reflect.Float64:
+		fv, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		v.SetFloat(fv)
+	}
+
+	return nil
+}
+
+// isScalar reports whether values of kind k can be set by setScalar.
+func isScalar(k reflect.Kind) bool {
+	switch k { //nolint:exhaustive // ok
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,79 @@
+package confetti
+
+import (
+	"reflect"
+)
+
+// defaultsLoader populates struct fields from their `default` struct tags.
+// It is always applied by Load as the first layer, before any other loader, and once more
+// after all of them, for the pointers to structs which they allocated.
+type defaultsLoader struct{}
+
+func (defaultsLoader) Load(config any, opts *Options) error {
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}.withCustom(opts)
+
+	return loadDefaults(reflect.ValueOf(config).Elem(), "", dec, opts)
+}
+
+func (defaultsLoader) String() string {
+	return "default"
+}
+
+// loadDefaults recursively sets the zero valued fields of v from their `default` tags,
+// using the same conversion rules as the env loader (slices and maps are split by DefaultSeparator).
+// Fields that already hold a value (e.g. a pre-populated config) or that a loader set are left
+// untouched, and so are nil pointers to structs, which are only descended into when non-nil.
+func loadDefaults(v reflect.Value, path string, dec decoder, opts *Options) error {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		fieldVal := v.Field(i)
+
+		if !fieldVal.CanSet() {
+			continue
+		}
+
+		fieldPath := joinPath(path, field.Name)
+
+		if fieldVal.Kind() == reflect.Struct && dec.nested(fieldVal.Type()) {
+			if err := loadDefaults(fieldVal, fieldPath, dec, opts); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if fieldVal.Kind() == reflect.Ptr && dec.nested(fieldVal.Type()) {
+			if !fieldVal.IsNil() {
+				if err := loadDefaults(fieldVal.Elem(), fieldPath, dec, opts); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if _, set := opts.touched[fieldPath]; !ok || set || !fieldVal.IsZero() {
+			continue
+		}
+
+		if err := dec.setValue(fieldVal, def, "default "+fieldPath); err != nil {
+			return err
+		}
+
+		opts.MarkSet(fieldPath, "")
+	}
+
+	return nil
+}
+
+// joinPath joins a (possibly empty) parent field path and a field name with a dot.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,157 @@
+package confetti
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// dotEnvLoader loads config from a .env file, as the env loader would from the process env.
+type dotEnvLoader struct {
+	envLoader
+	path string
+}
+
+func (d dotEnvLoader) Load(config any, opts *Options) (err error) {
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return
+	}
+
+	vars, err := parseDotEnv(string(data), d.path)
+	if err != nil {
+		return
+	}
+
+	return d.load(config, envVars{vars: vars, kind: "dotenv"}, opts)
+}
+
+func (d dotEnvLoader) String() string {
+	return "dotenv:" + d.path
+}
+
+// File returns the path of the .env file (see FileLoader).
+func (d dotEnvLoader) File() string {
+	return d.path
+}
+
+// parseDotEnv parses the content of a .env file (whose path is only used in errors) into a map
+// of env vars. Each line holds a NAME=value pair, optionally preceded by "export". Blank lines
+// and lines starting with # are ignored. Values can be:
+//   - unquoted: surrounding spaces and comments (a # at the start or after whitespace) are dropped;
+//   - single quoted: taken literally;
+//   - double quoted: may span several lines and support the \n, \r, \t, \" and \\ escapes.
+//
+// Except in single quoted values, ${NAME} is replaced with the value of NAME, as defined
+// earlier in the file or, failing that, in the process env (or with "", if unset).
+func parseDotEnv(data, path string) (map[string]string, error) {
+	vars := map[string]string{}
+	lookup := func(name string) string {
+		if val, ok := vars[name]; ok {
+			return val
+		}
+
+		return os.Getenv(name)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		n, line := i+1, strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(line) == "" || line[0] == '#' {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimLeft(rest, " \t")
+		}
+
+		name, val, ok := strings.Cut(line, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("%s:%d: invalid line %q", path, n, strings.TrimSpace(lines[i]))
+		}
+
+		switch val = strings.TrimLeft(val, " \t"); {
+		case strings.HasPrefix(val, "'"):
+			end := strings.IndexByte(val[1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("%s:%d: unterminated quoted value of %s", path, n, name)
+			}
+
+			val = val[1 : end+1]
+		case strings.HasPrefix(val, `"`):
+			val = val[1:]
+
+			end := closingQuote(val)
+			for end < 0 {
+				if i++; i == len(lines) {
+					return nil, fmt.Errorf("%s:%d: unterminated quoted value of %s", path, n, name)
+				}
+
+				val += "\n" + lines[i]
+				end = closingQuote(val)
+			}
+
+			val = expandDotEnv(val[:end], true, lookup)
+		default:
+			for j := range len(val) {
+				if val[j] == '#' && (j == 0 || val[j-1] == ' ' || val[j-1] == '\t') {
+					val = val[:j]
+					break
+				}
+			}
+
+			val = expandDotEnv(strings.TrimSpace(val), false, lookup)
+		}
+
+		vars[name] = val
+	}
+
+	return vars, nil
+}
+
+// closingQuote returns the index of the first double quote in s not escaped by a backslash, or -1.
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// expandDotEnv replaces the ${NAME} references in s with lookup(NAME) and,
+// if escapes is true, interprets the backslash escapes.
+func expandDotEnv(s string, escapes bool, lookup func(string) string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case escapes && c == '\\' && i+1 < len(s):
+			i++
+
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+		case c == '$' && strings.HasPrefix(s[i:], "${") && strings.IndexByte(s[i:], '}') > 0:
+			end := i + strings.IndexByte(s[i:], '}')
+			b.WriteString(lookup(s[i+2 : end]))
+			i = end
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
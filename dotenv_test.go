@@ -0,0 +1,37 @@
+//nolint:testpackage // ok
+package confetti
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in   string
+		want map[string]string
+	}{
+		{"FOO=1", map[string]string{"FOO": "1"}},
+		{"export FOO=1", map[string]string{"FOO": "1"}},
+		{"export\tFOO=1", map[string]string{"FOO": "1"}},
+		{"export  \t FOO=1", map[string]string{"FOO": "1"}},
+		{"exportFOO=1", map[string]string{"exportFOO": "1"}},
+		{"FOO=", map[string]string{"FOO": ""}},
+		{"FOO= #comment", map[string]string{"FOO": ""}},
+		{"FOO=#comment", map[string]string{"FOO": ""}},
+		{"FOO=bar #comment", map[string]string{"FOO": "bar"}},
+		{"FOO=bar\t#comment", map[string]string{"FOO": "bar"}},
+		{"FOO=bar#baz", map[string]string{"FOO": "bar#baz"}},
+		{`FOO="bar #baz"`, map[string]string{"FOO": "bar #baz"}},
+		{"FOO='#bar'", map[string]string{"FOO": "#bar"}},
+	}
+
+	for _, c := range cases {
+		got, err := parseDotEnv(c.in, "test.env")
+		if err != nil || !maps.Equal(got, c.want) {
+			t.Errorf("parseDotEnv(%q) = %v, %v; want %v", c.in, got, err, c.want)
+		}
+	}
+}
@@ -1,53 +1,103 @@
 package confetti
 
 import (
-	"cmp"
 	"errors"
 	"fmt"
 	"maps"
 	"os"
 	"reflect"
 	"slices"
-	"strconv"
 	"strings"
-	"time"
 )
 
 // envLoader loads config from environment variables.
 // If string is not empty it is used as a prefix for the environment variable.
 type envLoader struct {
-	prefix    string
-	separator string
+	prefix string
+	decoder
 }
 
-const DefaultSeparator = ","
+const (
+	DefaultSeparator   = ","
+	DefaultKVSeparator = "="
+)
+
+func (e envLoader) Load(config any, opts *Options) (err error) {
+	return e.load(config, processEnv(), opts)
+}
 
-func (e envLoader) Load(config any, ownConfig *confetti) (err error) {
+// load populates config from env, the set of env vars to read.
+func (e envLoader) load(config any, env envVars, opts *Options) (err error) {
 	var errOnUnknown bool
 
-	if ownConfig != nil {
-		errOnUnknown = ownConfig.errOnUnknown
+	if opts != nil {
+		errOnUnknown = opts.ErrOnUnknown
+		opts.envPrefixes = append(opts.envPrefixes, e.prefix)
+	}
+
+	dec := e.withCustom(opts)
+	env.fields = map[string]bool{}
+
+	envNames(reflect.TypeOf(config).Elem(), strings.ToUpper(e.prefix), dec, map[reflect.Type]bool{}, func(name string, _ reflect.StructField) bool {
+		env.fields[name] = true
+		return true
+	})
+
+	if err = loadEnv(config, e.prefix, "", env, dec, opts); err != nil || !errOnUnknown || e.prefix == "" {
+		return
 	}
 
-	return loadEnv(config, e.prefix, e.separator, errOnUnknown)
+	return unknownEnv(reflect.TypeOf(config).Elem(), e.prefix, env, dec)
 }
 
-// loadEnv recursively sets struct fields from env vars for arbitrarily deep nesting.
-// If prefix is not empty, it is used as a prefix for the environment variable.
-func loadEnv(config any, prefix, separator string, errOnUnknown bool) error {
-	var unknowns map[string]struct{}
+func (e envLoader) String() string {
+	return "env:" + e.prefix
+}
 
-	if prefix != "" && errOnUnknown {
-		unknowns = map[string]struct{}{}
+// envVars is a set of env vars, either the process ones or those parsed from a .env file.
+type envVars struct {
+	vars map[string]string
+	// kind names the source of the vars (e.g. "env"), in the fields provenance.
+	kind string
+	// fields holds the names of the env vars consulted for the config fields (see envNames),
+	// which are never taken as map entries (e.g. MYAPP_LABELS_EXTRA for a LabelsExtra field).
+	fields map[string]bool
+}
 
-		for _, env := range os.Environ() {
-			parts := strings.SplitN(env, "=", 2)
-			if len(parts) == 2 && strings.HasPrefix(parts[0], strings.ToUpper(prefix)+"_") {
-				unknowns[parts[0]] = struct{}{}
-			}
+// processEnv returns the env vars of the current process.
+func processEnv() envVars {
+	vars := map[string]string{}
+
+	for _, env := range os.Environ() {
+		if name, val, ok := strings.Cut(env, "="); ok {
+			vars[name] = val
 		}
 	}
 
+	return envVars{vars: vars, kind: "env"}
+}
+
+func (e envVars) lookup(name string) (val string, ok bool) {
+	val, ok = e.vars[name]
+	return
+}
+
+// hasEntries reports whether any env var is a NAME_KEY=value entry of the map field whose
+// env var is name (see loadEnvMap).
+func (e envVars) hasEntries(name string) bool {
+	for n := range e.vars {
+		if key, ok := strings.CutPrefix(n, name+"_"); ok && key != "" && !e.fields[n] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadEnv recursively sets struct fields from env vars for arbitrarily deep nesting.
+// If prefix is not empty, it is used as a prefix for the environment variable.
+// The path is the (dot separated) field path of config within the top level config.
+func loadEnv(config any, prefix, path string, env envVars, dec decoder, opts *Options) error {
 	v := reflect.ValueOf(config)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
 		return errors.New("config must be pointer to struct")
@@ -68,133 +118,259 @@ func loadEnv(config any, prefix, separator string, errOnUnknown bool) error {
 			continue
 		}
 
-		// Check for struct tag override.
-		tagEnv := field.Tag.Get("env")
-		name := cmp.Or(tagEnv, camelToUpperSnake(field.Name))
+		envName, fieldPath := envVarName(field, prefix), joinPath(path, field.Name)
 
-		envName := name
-		if prefix != "" && tagEnv == "" {
-			envName = prefix + "_" + name
+		if fieldVal.Kind() == reflect.Struct && dec.nested(fieldVal.Type()) {
+			if err := loadEnv(fieldVal.Addr().Interface(), envName, fieldPath, env, dec, opts); err != nil {
+				return err
+			}
+
+			continue
 		}
 
-		if fieldVal.Kind() == reflect.Struct {
-			subPrefix := name
-			if prefix != "" && tagEnv == "" {
-				subPrefix = prefix + "_" + name
+		// Pointers to structs are only allocated if any of their env vars is set.
+		if fieldVal.Kind() == reflect.Ptr && dec.nested(fieldVal.Type()) {
+			if fieldVal.IsNil() {
+				if !envPresent(fieldVal.Type().Elem(), envName, env, dec) {
+					continue
+				}
+
+				fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+			}
+
+			if err := loadEnv(fieldVal.Interface(), envName, fieldPath, env, dec, opts); err != nil {
+				return err
 			}
 
-			if err := loadEnv(fieldVal.Addr().Interface(), subPrefix, separator, errOnUnknown); err != nil {
+			continue
+		}
+
+		if fieldVal.Kind() == reflect.Map {
+			found, err := dec.loadEnvMap(fieldVal, envName, env)
+			if err != nil {
 				return err
 			}
 
+			if len(found) > 0 {
+				opts.MarkSet(fieldPath, env.kind+":"+strings.Join(found, ","))
+			}
+
 			continue
 		}
 
-		val, ok := os.LookupEnv(envName)
+		val, ok := env.lookup(envName)
 		if !ok {
 			continue
 		}
 
-		delete(unknowns, envName)
+		if err := dec.setValue(fieldVal, val, "env "+envName); err != nil {
+			return err
+		}
 
-		switch fieldVal.Kind() { //nolint:exhaustive // ok
-		case reflect.String:
-			fieldVal.SetString(val)
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if fieldVal.Type().PkgPath() == "time" && fieldVal.Type().Name() == "Duration" {
-				d, err := time.ParseDuration(val)
-				if err != nil {
-					return fmt.Errorf("env %s: %w", envName, err)
-				}
+		opts.MarkSet(fieldPath, env.kind+":"+envName)
+	}
 
-				fieldVal.SetInt(int64(d))
+	return nil
+}
 
-				break
-			}
+// envPresent reports whether any of the env vars loadEnv would consult
+// for the fields of struct type t (and of its nested structs) is set,
+// including the NAME_KEY=value entries of map fields.
+func envPresent(t reflect.Type, prefix string, env envVars, dec decoder) bool {
+	return !envNames(t, prefix, dec, map[reflect.Type]bool{}, func(name string, field reflect.StructField) bool {
+		_, ok := env.lookup(name)
+		return !ok && (field.Type.Kind() != reflect.Map || !env.hasEntries(name))
+	})
+}
 
-			iv, err := strconv.ParseInt(val, 10, 64)
-			if err != nil {
-				return fmt.Errorf("env %s: %w", envName, err)
-			}
+// envNames calls fn with the name of each env var loadEnv would consult for the fields of
+// struct type t (and of its nested structs) and the field it is for (the entries of map fields
+// can also be given as NAME_KEY=value), as long as fn returns true. It reports whether it did not
+// stop early.
+func envNames(t reflect.Type, prefix string, dec decoder, seen map[reflect.Type]bool, fn func(name string, field reflect.StructField) bool) bool {
+	if seen[t] {
+		return true
+	}
 
-			fieldVal.SetInt(iv)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			uv, err := strconv.ParseUint(val, 10, 64)
-			if err != nil {
-				return fmt.Errorf("env %s: %w", envName, err)
-			}
+	seen[t] = true
+	defer delete(seen, t)
 
-			fieldVal.SetUint(uv)
-		case reflect.Bool:
-			bv, err := parseBool(val)
-			if err != nil {
-				return fmt.Errorf("env %s: %w", envName, err)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ft := envVarName(field, prefix), field.Type
+		if dec.nested(ft) {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
 			}
 
-			fieldVal.SetBool(bv)
-		case reflect.Float32, reflect.Float64:
-			fv, err := strconv.ParseFloat(val, 64)
-			if err != nil {
-				return fmt.Errorf("env %s: %w", envName, err)
+			if !envNames(ft, name, dec, seen, fn) {
+				return false
 			}
 
-			fieldVal.SetFloat(fv)
-		case reflect.Slice:
-			elemKind := fieldVal.Type().Elem().Kind()
-			parts := strings.Split(val, separator)
-			slice := reflect.MakeSlice(fieldVal.Type(), len(parts), len(parts))
-
-			for j, part := range parts {
-				part = strings.TrimSpace(part)
-
-				switch elemKind {
-				case reflect.String:
-					slice.Index(j).SetString(part)
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					iv, err := strconv.ParseInt(part, 10, 64)
-					if err != nil {
-						return fmt.Errorf("env %s[%d]: %w", envName, j, err)
-					}
-
-					slice.Index(j).SetInt(iv)
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					uv, err := strconv.ParseUint(part, 10, 64)
-					if err != nil {
-						return fmt.Errorf("env %s[%d]: %w", envName, j, err)
-					}
-
-					slice.Index(j).SetUint(uv)
-				case reflect.Float32, reflect.Float64:
-					fv, err := strconv.ParseFloat(part, 64)
-					if err != nil {
-						return fmt.Errorf("env %s[%d]: %w", envName, j, err)
-					}
-
-					slice.Index(j).SetFloat(fv)
-				case reflect.Bool:
-					bv, err := parseBool(part)
-					if err != nil {
-						return fmt.Errorf("env %s[%d]: %w", envName, j, err)
-					}
-
-					slice.Index(j).SetBool(bv)
-				default:
-					return fmt.Errorf("env %s: unsupported slice element type %s", envName, elemKind)
-				}
+			continue
+		}
+
+		if !fn(name, field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UnknownEnvError reports the env vars which have the prefix of an env loader (see WithEnv and
+// WithErrOnUnknown) but are not consulted for any config field. It wraps ErrUnknownFields.
+type UnknownEnvError struct {
+	// Names lists the unknown env vars, sorted.
+	Names []string
+	// Suggestions maps the unknown env vars that look like a typo to the expected env var they resemble most.
+	Suggestions map[string]string
+}
+
+func (e *UnknownEnvError) Error() string {
+	names := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		names[i] = name
+		if s, ok := e.Suggestions[name]; ok {
+			names[i] += " (did you mean " + s + "?)"
+		}
+	}
+
+	return "unknown environment variables: " + strings.Join(names, ", ")
+}
+
+func (e *UnknownEnvError) Unwrap() error {
+	return ErrUnknownFields
+}
+
+// unknownEnv returns an UnknownEnvError listing the env vars starting with prefix which
+// are not consulted for any field of struct type t, or nil if there are none.
+func unknownEnv(t reflect.Type, prefix string, env envVars, dec decoder) error {
+	prefix = strings.ToUpper(prefix)
+	expected, mapPrefixes := map[string]bool{}, []string{}
+
+	envNames(t, prefix, dec, map[reflect.Type]bool{}, func(name string, field reflect.StructField) bool {
+		if expected[name] = true; field.Type.Kind() == reflect.Map {
+			mapPrefixes = append(mapPrefixes, name+"_")
+		}
+
+		return true
+	})
+
+	e := &UnknownEnvError{Suggestions: map[string]string{}}
+
+	for _, name := range slices.Sorted(maps.Keys(env.vars)) {
+		if !strings.HasPrefix(name, prefix+"_") || expected[name] || slices.ContainsFunc(mapPrefixes, func(p string) bool {
+			return strings.HasPrefix(name, p)
+		}) {
+			continue
+		}
+
+		e.Names = append(e.Names, name)
+
+		if s := suggest(name, slices.Sorted(maps.Keys(expected))); s != "" {
+			e.Suggestions[name] = s
+		}
+	}
+
+	if len(e.Names) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// suggest returns the candidate closest to name, if close enough to be a likely typo, or "".
+func suggest(name string, candidates []string) (best string) {
+	bestDist := max(2, len(name)/5) + 1
+
+	for _, c := range candidates {
+		if d := editDistance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+
+	return
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := range len(a) {
+		cur[0] = i + 1
+
+		for j := range len(b) {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
 			}
 
-			fieldVal.Set(slice)
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
 		}
+
+		prev, cur = cur, prev
 	}
 
-	if len(unknowns) > 0 {
-		unk := slices.Collect(maps.Keys(unknowns))
-		slices.Sort(unk)
+	return prev[len(b)]
+}
 
-		return fmt.Errorf("unknown environment variables: %v", unk)
+// envVarName returns the env var name of field (or the prefix of its own fields,
+// for nested structs): either its `env` tag or its UPPER_SNAKE_CASE name, prefixed.
+func envVarName(field reflect.StructField, prefix string) string {
+	tagEnv, _ := envTag(field)
+	if tagEnv != "" {
+		return tagEnv
 	}
 
-	return nil
+	name := camelToUpperSnake(field.Name)
+	if prefix != "" {
+		name = strings.ToUpper(prefix) + "_" + name
+	}
+
+	return name
+}
+
+// envTag parses the `env` struct tag of field, which has the form "NAME[,required]".
+func envTag(field reflect.StructField) (name string, required bool) {
+	name, opts, _ := strings.Cut(field.Tag.Get("env"), ",")
+
+	return name, slices.Contains(strings.Split(opts, ","), "required")
+}
+
+// loadEnvMap populates the map v from the env var envName, holding key/value pairs,
+// then from the env vars named envName_KEY, each holding the value of (lowercased) KEY,
+// except for those consulted for other fields. It returns the names of the env vars found.
+func (d decoder) loadEnvMap(v reflect.Value, envName string, env envVars) (found []string, err error) {
+	if val, ok := env.lookup(envName); ok {
+		if err = d.setValue(v, val, "env "+envName); err != nil {
+			return
+		}
+
+		found = append(found, envName)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(env.vars)) {
+		key, ok := strings.CutPrefix(name, envName+"_")
+		if !ok || key == "" || env.fields[name] {
+			continue
+		}
+
+		if err = d.setMapEntry(v, strings.ToLower(key), env.vars[name], "env "+name); err != nil {
+			return
+		}
+
+		found = append(found, name)
+	}
+
+	return
 }
 
 // parseBool parses a string into a boolean value, accepting
@@ -0,0 +1,213 @@
+package confetti
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// EnvDocFormat is the output format of WriteEnvDoc.
+type EnvDocFormat string
+
+const (
+	// EnvDocMarkdown renders the env vars as a Markdown table.
+	EnvDocMarkdown EnvDocFormat = "markdown"
+	// EnvDocText renders the env vars as a plain text table.
+	EnvDocText EnvDocFormat = "text"
+	// EnvDocDotEnv renders the env vars as a sample .env file (see WithDotEnv),
+	// each set to its default value and preceded by its description.
+	EnvDocDotEnv EnvDocFormat = "dotenv"
+)
+
+// EnvVar describes an env var consulted by the env loaders (see WithEnv).
+type EnvVar struct {
+	// Name is the name of the env var (e.g. MYAPP_DB_HOST).
+	Name string
+	// Type is the Go type of the field it is for (e.g. "time.Duration").
+	Type string
+	// Default is the `default` tag of the field, if any.
+	Default string
+	// Required is true if the field is required (see Load).
+	Required bool
+	// Description is the `desc` tag of the field, if any.
+	Description string
+	// Map is true for map fields, whose entries can also be given as Name_KEY=value.
+	Map bool
+}
+
+// EnvVars lists the env vars WithEnv(prefix) consults for the config cfg (a struct or a pointer
+// to one), in field order, so that they can be documented (see WriteEnvDoc).
+func EnvVars(cfg any, prefix string) ([]EnvVar, error) {
+	t := reflect.TypeOf(cfg)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a struct or a pointer to one (got %T)", cfg)
+	}
+
+	vars := []EnvVar{}
+
+	envNames(t, strings.ToUpper(prefix), decoder{}, map[reflect.Type]bool{}, func(name string, field reflect.StructField) bool {
+		_, envRequired := envTag(field)
+		vars = append(vars, EnvVar{
+			Name:        name,
+			Type:        field.Type.String(),
+			Default:     field.Tag.Get("default"),
+			Required:    envRequired || field.Tag.Get("required") == "true",
+			Description: field.Tag.Get("desc"),
+			Map:         field.Type.Kind() == reflect.Map,
+		})
+
+		return true
+	})
+
+	return vars, nil
+}
+
+// WriteEnvDoc writes the documentation of the env vars WithEnv(prefix) consults for the config
+// cfg (see EnvVars) to w, in the given format. The `desc` struct tag describes a field.
+func WriteEnvDoc(w io.Writer, cfg any, prefix string, format EnvDocFormat) error {
+	vars, err := EnvVars(cfg, prefix)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case EnvDocMarkdown:
+		return writeEnvMarkdown(w, vars)
+	case EnvDocText:
+		return writeEnvText(w, vars)
+	case EnvDocDotEnv:
+		return writeDotEnv(w, vars)
+	default:
+		return fmt.Errorf("unsupported env doc format %q", format)
+	}
+}
+
+// names returns the name of v and, for maps, that of its entries.
+func (v EnvVar) names() []string {
+	if v.Map {
+		return []string{v.Name, v.Name + "_<KEY>"}
+	}
+
+	return []string{v.Name}
+}
+
+func writeEnvMarkdown(w io.Writer, vars []EnvVar) error {
+	rows := [][]string{{"Name", "Type", "Default", "Required", "Description"}}
+
+	for _, v := range vars {
+		names := v.names()
+		for i, name := range names {
+			names[i] = "`" + name + "`"
+		}
+
+		rows = append(rows, []string{strings.Join(names, ", "), "`" + v.Type + "`", v.Default, yesNo(v.Required), v.Description})
+	}
+
+	widths := make([]int, len(rows[0]))
+
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = strings.ReplaceAll(cell, "|", `\|`)
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]), 3)
+		}
+	}
+
+	sep := make([]string, len(widths))
+	for i, width := range widths {
+		sep[i] = strings.Repeat("-", width)
+	}
+
+	for _, row := range slices.Insert(rows, 1, sep) {
+		line := "|"
+		for i, cell := range row {
+			line += " " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " |"
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeEnvText(w io.Writer, vars []EnvVar) error {
+	var b strings.Builder
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION") //nolint:errcheck // it cannot fail.
+
+	for _, v := range vars {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck // it cannot fail.
+			strings.Join(v.names(), ", "), v.Type, v.Default, yesNo(v.Required), v.Description)
+	}
+
+	tw.Flush() //nolint:errcheck // it cannot fail.
+
+	// Drop the padding of the empty descriptions.
+	for line := range strings.Lines(b.String()) {
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " \n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeDotEnv(w io.Writer, vars []EnvVar) error {
+	for i, v := range vars {
+		about := v.Type
+		if v.Required {
+			about += ", required"
+		}
+
+		if v.Map {
+			about += ", or one " + v.Name + "_<KEY> var per entry"
+		}
+
+		if v.Description != "" {
+			about = v.Description + " (" + about + ")"
+		}
+
+		sep := "\n"
+		if i == 0 {
+			sep = ""
+		}
+
+		if _, err := fmt.Fprintf(w, "%s# %s\n%s=%s\n", sep, about, v.Name, dotEnvQuote(v.Default)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dotEnvQuote quotes the value val for a .env file (see parseDotEnv), if needed.
+func dotEnvQuote(val string) string {
+	switch {
+	case !strings.ContainsAny(val, " \t\n\"'#$\\"):
+		return val
+	case !strings.ContainsAny(val, "'\n"):
+		return "'" + val + "'"
+	default:
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`)
+		return `"` + r.Replace(val) + `"`
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
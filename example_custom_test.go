@@ -0,0 +1,61 @@
+package confetti_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alexaandru/confetti"
+)
+
+// mapLoader is a custom loader that populates the Host field from a map.
+type mapLoader map[string]string
+
+func (m mapLoader) Load(cfg any, opts *confetti.Options) error {
+	c, ok := cfg.(*ExampleConfig)
+	if !ok {
+		return fmt.Errorf("%s: unsupported config type %T", opts.Source, cfg)
+	}
+
+	host, ok := m["host"]
+	if !ok {
+		return errors.New(opts.Source + ": missing host")
+	}
+
+	c.Host = host
+	opts.MarkSet("Host", "")
+
+	if opts.ErrOnUnknown && len(m) > 1 {
+		return fmt.Errorf("%w: %s", confetti.ErrUnknownFields, opts.Source)
+	}
+
+	return nil
+}
+
+func (m mapLoader) String() string {
+	return "map"
+}
+
+func ExampleLoad_custom_loader() {
+	var prov confetti.Provenance
+
+	cfg := &ExampleConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithProvenance(&prov),
+		confetti.WithJSON([]byte(`{"Host":"jsonhost","Port":8080}`)),
+		mapLoader{"host": "maphost"},
+	)
+
+	fmt.Printf("Host=%s (from %s) Port=%d\n", cfg.Host, prov.Source("Host"), cfg.Port)
+	fmt.Println(err)
+
+	err = confetti.Load(cfg, confetti.WithErrOnUnknown(), mapLoader{"host": "maphost", "port": "1"})
+	fmt.Println(err)
+
+	err = confetti.Load(cfg, mapLoader{})
+	fmt.Println(err)
+	// Output:
+	// Host=maphost (from map) Port=8080
+	// <nil>
+	// unknown fields in config: map
+	// map: missing host
+}
@@ -0,0 +1,96 @@
+package confetti_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexaandru/confetti"
+)
+
+type Color int
+
+type Celsius float64
+
+func (c *Color) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	switch s {
+	case "red":
+		*c = 1
+	case "green":
+		*c = 2
+	default:
+		return fmt.Errorf("unknown color %q", s)
+	}
+
+	return nil
+}
+
+func ExampleLoad_env_decoders() {
+	os.Setenv("DECO_IP", "10.0.0.1")
+	os.Setenv("DECO_PEERS", "10.0.0.2, 10.0.0.3")
+	os.Setenv("DECO_ENDPOINT", "https://example.com/api")
+	os.Setenv("DECO_PATTERN", "^a+$")
+	os.Setenv("DECO_LEVEL", "warn")
+	os.Setenv("DECO_SINCE", "2024-01-02T03:04:05Z")
+	os.Setenv("DECO_COLOR", "green")
+	os.Setenv("DECO_TEMPS", "21.5C,-3C")
+
+	type DecodersConfig struct {
+		IP       net.IP
+		Peers    []net.IP
+		Endpoint url.URL
+		Pattern  *regexp.Regexp
+		Level    slog.Level
+		Since    time.Time
+		Color    Color
+		Temps    []Celsius
+		Missing  *url.URL
+	}
+
+	cfg := &DecodersConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithDecoder(reflect.TypeFor[Celsius](), func(s string) (any, error) {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(s, "C"), 64)
+			return Celsius(f), err
+		}),
+		confetti.WithEnv("DECO"),
+	)
+
+	fmt.Printf("IP=%s Peers=%v\n", cfg.IP, cfg.Peers)
+	fmt.Printf("Endpoint.Host=%s Pattern=%s\n", cfg.Endpoint.Host, cfg.Pattern)
+	fmt.Printf("Level=%s Since=%s\n", cfg.Level, cfg.Since.Format(time.DateOnly))
+	fmt.Printf("Color=%d Temps=%v Missing=%v\n", cfg.Color, cfg.Temps, cfg.Missing)
+	fmt.Println(err)
+	// Output:
+	// IP=10.0.0.1 Peers=[10.0.0.2 10.0.0.3]
+	// Endpoint.Host=example.com Pattern=^a+$
+	// Level=WARN Since=2024-01-02
+	// Color=2 Temps=[21.5 -3] Missing=<nil>
+	// <nil>
+}
+
+func ExampleLoad_env_decoders_error() {
+	os.Setenv("DECO2_COLOR", "blue")
+
+	type DecodersConfig struct {
+		Color Color
+	}
+
+	err := confetti.Load(&DecodersConfig{}, confetti.WithEnv("DECO2"))
+	fmt.Println(err)
+	// Output:
+	// env DECO2_COLOR: unknown color "blue"
+}
@@ -0,0 +1,92 @@
+package confetti_test
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alexaandru/confetti"
+)
+
+type DefaultsConfig struct {
+	Host    string        `default:"localhost"`
+	Port    int           `default:"8080"`
+	Debug   bool          `default:"yes"`
+	Timeout time.Duration `default:"1m30s"`
+	Ports   []int         `default:"80,443"`
+	Nested  struct {
+		Ratio float64 `default:"0.5"`
+	}
+}
+
+func ExampleLoad_defaults() {
+	os.Setenv("DEFS_PORT", "9090")
+
+	cfg := &DefaultsConfig{Host: "prepopulated"}
+	err := confetti.Load(cfg, confetti.WithEnv("DEFS"))
+
+	fmt.Printf("Host=%s\n", cfg.Host)
+	fmt.Printf("Port=%d\n", cfg.Port)
+	fmt.Printf("Debug=%v\n", cfg.Debug)
+	fmt.Printf("Timeout=%s\n", cfg.Timeout)
+	fmt.Printf("Ports=%v\n", cfg.Ports)
+	fmt.Printf("Nested.Ratio=%v\n", cfg.Nested.Ratio)
+	fmt.Println(err)
+	// Output:
+	// Host=prepopulated
+	// Port=9090
+	// Debug=true
+	// Timeout=1m30s
+	// Ports=[80 443]
+	// Nested.Ratio=0.5
+	// <nil>
+}
+
+func ExampleLoad_defaults_error() {
+	type BadDefaults struct {
+		Nested struct {
+			Port int `default:"eighty"`
+		}
+	}
+
+	err := confetti.Load(&BadDefaults{}, confetti.WithJSON([]byte(`{}`)))
+	fmt.Println(err)
+	// Output:
+	// default Nested.Port: strconv.ParseInt: parsing "eighty": invalid syntax
+}
+
+type PoolConfig struct {
+	Pool *struct {
+		Size    int `default:"10"`
+		Timeout string
+	}
+}
+
+func ExampleLoad_defaults_pointers() {
+	os.Setenv("PX_POOL_TIMEOUT", "5s")
+
+	cfg := &PoolConfig{}
+	err := confetti.Load(cfg, confetti.WithEnv("PX"))
+
+	fmt.Println(cfg.Pool.Size, cfg.Pool.Timeout, err)
+
+	cfg = &PoolConfig{}
+	err = confetti.Load(cfg, confetti.WithJSON([]byte(`{"Pool": {"Timeout": "1s"}}`)))
+
+	fmt.Println(cfg.Pool.Size, cfg.Pool.Timeout, err)
+
+	cfg = &PoolConfig{}
+	err = confetti.Load(cfg, confetti.WithJSON([]byte(`{"Pool": {"Size": 0}}`)))
+
+	fmt.Println(cfg.Pool.Size, cfg.Pool.Timeout, err)
+
+	cfg = &PoolConfig{}
+	err = confetti.Load(cfg, confetti.WithJSON([]byte(`{}`)))
+
+	fmt.Println(cfg.Pool, err)
+	// Output:
+	// 10 5s <nil>
+	// 10 1s <nil>
+	// 0  <nil>
+	// <nil> <nil>
+}
@@ -0,0 +1,72 @@
+package confetti_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+func ExampleWithDotEnv() {
+	file := "test_config.env"
+	data := `# Sample .env file
+export MYAPP_HOST=localhost # the host
+MYAPP_PORT = 8080
+MYAPP_DEBUG='yes'
+MYAPP_NESTED_VALUE="line1
+line2\t${MYAPP_HOST}:${MYAPP_PORT}"
+MYAPP_NESTED_DEEP_FOO='${MYAPP_HOST}'
+MYAPP_STRS=a,b,c
+`
+
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	cfg := &ExampleConfig{}
+	if err := confetti.Load(cfg, confetti.WithDotEnv(file, "MYAPP")); err != nil {
+		panic(err)
+	}
+
+	_, set := os.LookupEnv("MYAPP_HOST")
+
+	fmt.Printf("Host=%s\n", cfg.Host)
+	fmt.Printf("Port=%d\n", cfg.Port)
+	fmt.Printf("Debug=%v\n", cfg.Debug)
+	fmt.Printf("Nested.Value=%q\n", cfg.Nested.Value)
+	fmt.Printf("Nested.Deep.Foo=%s\n", cfg.Nested.Deep.Foo)
+	fmt.Printf("Strs=%v\n", cfg.Strs)
+	fmt.Printf("Env set=%v\n", set)
+	// Output:
+	// Host=localhost
+	// Port=8080
+	// Debug=true
+	// Nested.Value="line1\nline2\tlocalhost:8080"
+	// Nested.Deep.Foo=${MYAPP_HOST}
+	// Strs=[a b c]
+	// Env set=false
+}
+
+func ExampleWithDotEnv_errors() {
+	file := "test_config.env"
+	defer os.Remove(file)
+
+	cfg := &ExampleConfig{}
+
+	for _, data := range []string{"MYAPP_HOST\n", "\nMYAPP_HOST=\"localhost\n", "MYAPP_PORT=http\n", "MYAPP_UNKNOWN=1\n"} {
+		if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+			panic("failed to write test file: " + err.Error())
+		}
+
+		fmt.Println(confetti.Load(cfg, confetti.WithErrOnUnknown(), confetti.WithDotEnv(file, "MYAPP")))
+	}
+
+	fmt.Println(confetti.Load(cfg, confetti.WithDotEnv("no_such_file.env", "MYAPP")))
+	// Output:
+	// test_config.env:1: invalid line "MYAPP_HOST"
+	// test_config.env:2: unterminated quoted value of MYAPP_HOST
+	// env MYAPP_PORT: strconv.ParseInt: parsing "http": invalid syntax
+	// unknown environment variables: MYAPP_UNKNOWN
+	// open no_such_file.env: no such file or directory
+}
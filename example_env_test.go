@@ -1,6 +1,7 @@
 package confetti_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -206,7 +207,40 @@ func ExampleLoad_env_error_on_unknown() {
 	fmt.Println(err)
 	// Output:
 	// Port=9999 Debug=true Nested.Value=tagged
-	// unknown environment variables: [MYAPP4_UNUSED]
+	// unknown environment variables: MYAPP4_UNUSED
+}
+
+func ExampleUnknownEnvError() {
+	os.Setenv("MYAPP5_NESTED_VALUE", "foo")
+	os.Setenv("MYAPP5_NESTED_DEEP_FOO", "bar")
+	os.Setenv("MYAPP5_LABELS_TEAM", "core")
+	os.Setenv("MYAPP5_PROT", "8080")
+	os.Setenv("MYAPP5_NESTED_VALEU", "foo")
+	os.Setenv("MYAPP5_UNUSED", "unknown")
+
+	type Config struct {
+		Port   int
+		Labels map[string]string
+		Nested struct {
+			Value string
+			Deep  struct {
+				Foo string
+			}
+		}
+	}
+
+	cfg := &Config{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), confetti.WithEnv("MYAPP5"))
+
+	var unkErr *confetti.UnknownEnvError
+
+	fmt.Println(cfg.Nested.Value, cfg.Nested.Deep.Foo, cfg.Labels)
+	fmt.Println(errors.Is(err, confetti.ErrUnknownFields), errors.As(err, &unkErr), unkErr.Names)
+	fmt.Println(err)
+	// Output:
+	// foo bar map[team:core]
+	// true true [MYAPP5_NESTED_VALEU MYAPP5_PROT MYAPP5_UNUSED]
+	// unknown environment variables: MYAPP5_NESTED_VALEU (did you mean MYAPP5_NESTED_VALUE?), MYAPP5_PROT (did you mean MYAPP5_PORT?), MYAPP5_UNUSED
 }
 
 func ExampleLoad_env_with_acronyms() {
@@ -226,3 +260,122 @@ func ExampleLoad_env_with_acronyms() {
 	// SQS: sqs1; SNS: sns1
 	// <nil>
 }
+
+func ExampleLoad_env_pointers() {
+	os.Setenv("PTRS_MAX_RETRIES", "0")
+	os.Setenv("PTRS_NAME", "ptr")
+	os.Setenv("PTRS_DB_HOST", "db.local")
+	os.Setenv("PTRS_DB_TIMEOUT", "5s")
+
+	type DB struct {
+		Host    string
+		Port    *int
+		Timeout *time.Duration
+	}
+
+	type PtrConfig struct {
+		MaxRetries *int
+		Timeout    *int
+		Name       *string
+		Tags       *[]string
+		DB         *DB
+		Cache      *struct {
+			Size int
+		}
+	}
+
+	cfg := &PtrConfig{}
+	err := confetti.Load(cfg, confetti.WithEnv("PTRS"))
+
+	fmt.Printf("MaxRetries=%d Timeout=%v Name=%s Tags=%v\n", *cfg.MaxRetries, cfg.Timeout, *cfg.Name, cfg.Tags)
+	fmt.Printf("DB.Host=%s DB.Port=%v DB.Timeout=%s Cache=%v\n", cfg.DB.Host, cfg.DB.Port, *cfg.DB.Timeout, cfg.Cache)
+	fmt.Println(err)
+	// Output:
+	// MaxRetries=0 Timeout=<nil> Name=ptr Tags=<nil>
+	// DB.Host=db.local DB.Port=<nil> DB.Timeout=5s Cache=<nil>
+	// <nil>
+}
+
+func ExampleLoad_env_maps() {
+	os.Setenv("MAPS_LABELS", "team=core, tier=backend")
+	os.Setenv("MAPS_LABELS_TIER", "frontend")
+	os.Setenv("MAPS_LIMITS", "cpu=2,mem=512")
+	os.Setenv("MAPS_TIMEOUTS_READ", "5s")
+	os.Setenv("MAPS_TIMEOUTS_WRITE", "1m")
+	os.Setenv("MAPS2_LIMITS", "cpu:4;mem:1024")
+
+	type MapsConfig struct {
+		Labels   map[string]string
+		Limits   map[string]int
+		Timeouts map[string]time.Duration
+		Empty    map[string]bool
+	}
+
+	cfg := &MapsConfig{}
+	err := confetti.Load(cfg, confetti.WithEnv("MAPS"))
+
+	fmt.Printf("Labels=%v\n", cfg.Labels)
+	fmt.Printf("Limits=%v\n", cfg.Limits)
+	fmt.Printf("Timeouts=%v\n", cfg.Timeouts)
+	fmt.Printf("Empty=%v\n", cfg.Empty)
+	fmt.Println(err)
+
+	cfg = &MapsConfig{}
+	err = confetti.Load(cfg, confetti.WithEnv("MAPS2", ";", ":"))
+
+	fmt.Printf("Limits=%v\n", cfg.Limits)
+	fmt.Println(err)
+
+	err = confetti.Load(cfg, confetti.WithEnv("MAPS2"))
+	fmt.Println(err)
+	// Output:
+	// Labels=map[team:core tier:frontend]
+	// Limits=map[cpu:2 mem:512]
+	// Timeouts=map[read:5s write:1m0s]
+	// Empty=map[]
+	// <nil>
+	// Limits=map[cpu:4 mem:1024]
+	// <nil>
+	// env MAPS2_LIMITS: invalid key/value pair "cpu:4;mem:1024"
+}
+
+func ExampleLoad_env_maps_shadowed() {
+	os.Setenv("MAPS3_LABELS_TEAM", "core")
+	os.Setenv("MAPS3_LABELS_EXTRA", "x") // LabelsExtra's, not a Labels entry.
+
+	type Config struct {
+		Labels      map[string]string
+		LabelsExtra string
+	}
+
+	cfg := &Config{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), confetti.WithEnv("MAPS3"))
+
+	fmt.Println(cfg.Labels, cfg.LabelsExtra)
+	fmt.Println(err)
+	// Output:
+	// map[team:core] x
+	// <nil>
+}
+
+func ExampleLoad_env_maps_in_pointers() {
+	os.Setenv("MAPS4_OPT_LABELS_TEAM", "core")
+
+	type Config struct {
+		Opt *struct {
+			Labels map[string]string
+		}
+		Unset *struct {
+			Labels map[string]string
+		}
+	}
+
+	cfg := &Config{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), confetti.WithEnv("MAPS4"))
+
+	fmt.Println(cfg.Opt.Labels, cfg.Unset)
+	fmt.Println(err)
+	// Output:
+	// map[team:core] <nil>
+	// <nil>
+}
@@ -0,0 +1,78 @@
+package confetti_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/alexaandru/confetti"
+)
+
+type EnvDocConfig struct {
+	Host    string            `required:"true" desc:"The host to listen on"`
+	Port    int               `default:"8080" desc:"The port to listen on"`
+	Timeout time.Duration     `env:"HTTP_TIMEOUT" default:"30s"`
+	Greet   string            `default:"Hello, $USER"`
+	Labels  map[string]string `desc:"Labels | tags"`
+	DB      struct {
+		User     string `env:",required"`
+		Password confetti.Secret[string]
+	}
+}
+
+func ExampleWriteEnvDoc() {
+	if err := confetti.WriteEnvDoc(os.Stdout, EnvDocConfig{}, "myapp", confetti.EnvDocMarkdown); err != nil {
+		panic(err)
+	}
+	// Output:
+	// | Name                                 | Type                      | Default      | Required | Description           |
+	// | ------------------------------------ | ------------------------- | ------------ | -------- | --------------------- |
+	// | `MYAPP_HOST`                         | `string`                  |              | yes      | The host to listen on |
+	// | `MYAPP_PORT`                         | `int`                     | 8080         | no       | The port to listen on |
+	// | `HTTP_TIMEOUT`                       | `time.Duration`           | 30s          | no       |                       |
+	// | `MYAPP_GREET`                        | `string`                  | Hello, $USER | no       |                       |
+	// | `MYAPP_LABELS`, `MYAPP_LABELS_<KEY>` | `map[string]string`       |              | no       | Labels \| tags        |
+	// | `MYAPP_DB_USER`                      | `string`                  |              | yes      |                       |
+	// | `MYAPP_DB_PASSWORD`                  | `confetti.Secret[string]` |              | no       |                       |
+}
+
+func ExampleWriteEnvDoc_text() {
+	if err := confetti.WriteEnvDoc(os.Stdout, EnvDocConfig{}, "myapp", confetti.EnvDocText); err != nil {
+		panic(err)
+	}
+	// Output:
+	// NAME                              TYPE                     DEFAULT       REQUIRED  DESCRIPTION
+	// MYAPP_HOST                        string                                 yes       The host to listen on
+	// MYAPP_PORT                        int                      8080          no        The port to listen on
+	// HTTP_TIMEOUT                      time.Duration            30s           no
+	// MYAPP_GREET                       string                   Hello, $USER  no
+	// MYAPP_LABELS, MYAPP_LABELS_<KEY>  map[string]string                      no        Labels | tags
+	// MYAPP_DB_USER                     string                                 yes
+	// MYAPP_DB_PASSWORD                 confetti.Secret[string]                no
+}
+
+func ExampleWriteEnvDoc_dotenv() {
+	if err := confetti.WriteEnvDoc(os.Stdout, &EnvDocConfig{}, "myapp", confetti.EnvDocDotEnv); err != nil {
+		panic(err)
+	}
+	// Output:
+	// # The host to listen on (string, required)
+	// MYAPP_HOST=
+	//
+	// # The port to listen on (int)
+	// MYAPP_PORT=8080
+	//
+	// # time.Duration
+	// HTTP_TIMEOUT=30s
+	//
+	// # string
+	// MYAPP_GREET='Hello, $USER'
+	//
+	// # Labels | tags (map[string]string, or one MYAPP_LABELS_<KEY> var per entry)
+	// MYAPP_LABELS=
+	//
+	// # string, required
+	// MYAPP_DB_USER=
+	//
+	// # confetti.Secret[string]
+	// MYAPP_DB_PASSWORD=
+}
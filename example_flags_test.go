@@ -0,0 +1,106 @@
+package confetti_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+type FlagsConfig struct {
+	Host    string `usage:"the host to listen on"`
+	Port    int    `flag:"p" usage:"the port to listen on"`
+	Debug   bool
+	Tags    []string
+	Labels  map[string]string
+	Secret  string `flag:"-"`
+	Limits  *struct{ MaxConns int }
+	Nested  struct{ Deep struct{ Foo string } }
+	Timeout *int
+}
+
+func ExampleWithFlags() {
+	os.Setenv("MYAPP_FLAGS_HOST", "example.com")
+	os.Setenv("MYAPP_FLAGS_PORT", "80")
+
+	args := []string{"-p", "8080", "-debug", "-tags", "a,b", "-labels", "team=core", "-labels", "tier=1", "-nested-deep-foo", "bar"}
+
+	cfg := &FlagsConfig{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+
+	if err := confetti.Load(cfg, confetti.WithEnv("MYAPP_FLAGS"), confetti.WithFlags(fs, args)); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port, cfg.Debug, cfg.Tags, cfg.Labels, cfg.Nested.Deep.Foo)
+	fmt.Println(cfg.Limits == nil, cfg.Timeout == nil, fs.Lookup("secret") == nil)
+	// Output:
+	// example.com 8080 true [a b] map[team:core tier:1] bar
+	// true true true
+}
+
+func ExampleWithFlags_usage() {
+	cfg := &struct {
+		Host  string `usage:"the host to listen on"`
+		Port  int    `flag:"p" usage:"the port to listen on"`
+		Debug bool   `usage:"enable debug logs"`
+	}{Host: "localhost"}
+
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+
+	err := confetti.Load(cfg, confetti.WithFlags(fs, []string{"-h"}))
+	fmt.Println(err)
+	// Output:
+	// Usage of myapp:
+	//   -debug
+	//     	enable debug logs
+	//   -host value
+	//     	the host to listen on (default localhost)
+	//   -p value
+	//     	the port to listen on
+	// flag: help requested
+}
+
+func ExampleWithFlags_pointers() {
+	cfg := &FlagsConfig{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+
+	if err := confetti.Load(cfg, confetti.WithFlags(fs, []string{"-limits-max-conns", "10", "-timeout", "5"})); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Limits.MaxConns, *cfg.Timeout)
+	// Output:
+	// 10 5
+}
+
+func ExampleWithFlags_error() {
+	cfg := &FlagsConfig{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	fmt.Println(confetti.Load(cfg, confetti.WithFlags(fs, []string{"-p", "http"})))
+	// Output:
+	// invalid value "http" for flag -p: flag p: strconv.ParseInt: parsing "http": invalid syntax
+}
+
+type FlagsNode struct {
+	Name string
+	Next *FlagsNode
+}
+
+func ExampleWithFlags_recursive() {
+	cfg := &FlagsNode{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	// Recursive types are only descended into once, like the env loaders do.
+	fmt.Println(confetti.Load(cfg, confetti.WithFlags(fs, []string{"-name", "head"})), cfg.Name, cfg.Next == nil)
+	fmt.Println(confetti.Load(cfg, confetti.WithFlags(fs, []string{"-next-name", "tail"})))
+	// Output:
+	// <nil> head true
+	// flag provided but not defined: -next-name
+}
@@ -0,0 +1,75 @@
+package confetti_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+type InterpolationConfig struct {
+	DSN      string
+	Hosts    []string
+	Labels   map[string]string
+	Password confetti.Secret[string]
+	Literal  string
+}
+
+func ExampleWithInterpolation() {
+	os.Setenv("CONFETTI_DB_HOST", "db.internal")
+	os.Setenv("CONFETTI_DB", "${CONFETTI_DB_HOST}:${DB_PORT:-5432}")
+
+	defer os.Unsetenv("CONFETTI_DB_HOST")
+	defer os.Unsetenv("CONFETTI_DB")
+
+	data := `{
+		"DSN": "postgres://app@${CONFETTI_DB}/app",
+		"Hosts": ["${CONFETTI_DB_HOST}", "backup.internal"],
+		"Labels": {"region": "${CONFETTI_REGION:-us-east-1}"},
+		"Password": "${ssm:/myapp/db-password}",
+		"Literal": "$${NOT_EXPANDED}"
+	}`
+	prov := confetti.Provenance{}
+
+	cfg := &InterpolationConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithInterpolation(),
+		confetti.WithProvenance(&prov),
+		confetti.WithMockedSSM(&mockSSM{params: map[string]string{"/myapp/db-password": "s3cr3t"}}),
+		confetti.WithJSON([]byte(data)),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.DSN)
+	fmt.Println(cfg.Hosts, cfg.Labels, cfg.Literal)
+	fmt.Println(cfg.Password.Value(), prov.Source("Password"))
+	// Output:
+	// postgres://app@db.internal:5432/app
+	// [db.internal backup.internal] map[region:us-east-1] ${NOT_EXPANDED}
+	// s3cr3t ssm:/myapp/db-password
+}
+
+func ExampleWithInterpolation_errors() {
+	os.Setenv("CONFETTI_A", "x${CONFETTI_B}")
+	os.Setenv("CONFETTI_B", "y${CONFETTI_A}")
+
+	defer os.Unsetenv("CONFETTI_A")
+	defer os.Unsetenv("CONFETTI_B")
+
+	data := `{"DSN": "${CONFETTI_A}", "Hosts": ["${CONFETTI_UNSET}"], "Literal": "${ssm:/myapp/missing}"}`
+
+	cfg := &InterpolationConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithInterpolation(),
+		confetti.WithMockedSSM(&mockSSM{params: map[string]string{}}),
+		confetti.WithJSON([]byte(data)),
+	)
+	fmt.Println(errors.Is(err, confetti.ErrUnresolvedRefs))
+	fmt.Println(err)
+	// Output:
+	// true
+	// unresolved references: DSN: reference cycle CONFETTI_A -> CONFETTI_B -> CONFETTI_A; Hosts: ${CONFETTI_UNSET}; Literal: ${ssm:/myapp/missing}
+}
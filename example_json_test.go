@@ -2,8 +2,10 @@ package confetti_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/alexaandru/confetti"
 )
@@ -54,6 +56,33 @@ func ExampleLoad_json_bytes() {
 	// Nested.Deep.Foo=baz
 }
 
+func ExampleLoad_json_durations() {
+	type Config struct {
+		Timeout  time.Duration            `json:"timeout"`
+		Retry    *time.Duration           `json:"retry"`
+		Backoffs []time.Duration          `json:"backoffs"`
+		Limits   map[string]time.Duration `json:"limits"`
+		Interval time.Duration            `json:"interval"`
+	}
+
+	data := `{"timeout":"30s","retry":"1m","backoffs":["1s",2000000000],"limits":{"read":"5s"},"interval":1000}`
+
+	cfg := &Config{}
+	if err := confetti.Load(cfg, confetti.WithMerge(), confetti.WithJSON([]byte(data))); err != nil {
+		panic("Load failed: " + err.Error())
+	}
+
+	fmt.Println(cfg.Timeout, *cfg.Retry, cfg.Backoffs, cfg.Limits, cfg.Interval)
+
+	cfg = &Config{}
+	fmt.Println(confetti.Load(cfg, confetti.WithJSON([]byte(data)), confetti.WithMergePatch([]byte(`{"timeout":"10s"}`))), cfg.Timeout)
+	fmt.Println(confetti.Load(cfg, confetti.WithJSON([]byte(`{"timeout":"soon"}`))))
+	// Output:
+	// 30s 1m0s [1s 2s] map[read:5s] 1µs
+	// <nil> 10s
+	// json: cannot unmarshal string into Go struct field Config.timeout of type time.Duration
+}
+
 func ExampleLoad_json_reader() {
 	cfg := &ExampleConfig{}
 
@@ -136,3 +165,18 @@ func ExampleLoad_json_file_not_found() {
 	// Output:
 	// Error: open no_such_file.json: no such file or directory
 }
+
+func ExampleLoadContext_canceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := &ExampleConfig{}
+	err := confetti.LoadContext(ctx, cfg, confetti.WithJSON([]byte(jsonData)))
+	fmt.Printf("Error: %v\n", err)
+
+	err = confetti.LoadContext(ctx, cfg, confetti.WithErrOnUnknown(), confetti.WithJSON([]byte(jsonData)))
+	fmt.Printf("Error: %v\n", err)
+	// Output:
+	// Error: json:[]byte: context canceled
+	// Error: json:[]byte: context canceled
+}
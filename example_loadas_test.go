@@ -0,0 +1,39 @@
+package confetti_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+func ExampleLoadAs() {
+	os.Setenv("MYAPP_AS_HOST", "example.com")
+
+	cfg, err := confetti.LoadAs[DefaultsConfig](confetti.WithEnv("MYAPP_AS"))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port)
+
+	_, err = confetti.LoadAs[int]()
+	fmt.Println(err)
+	// Output:
+	// example.com 8080
+	// config must be a pointer to a struct (got *int)
+}
+
+func ExampleMustLoad() {
+	defer func() {
+		fmt.Println(recover())
+	}()
+
+	cfg := confetti.MustLoad[DefaultsConfig]()
+	fmt.Println(cfg.Host, cfg.Port)
+
+	confetti.MustLoad[DefaultsConfig](confetti.WithJSON([]byte(`{"Port":"http"}`)))
+	// Output:
+	// localhost 8080
+	// confetti: loading confetti_test.DefaultsConfig: json: cannot unmarshal string into Go struct field DefaultsConfig.Port of type int
+}
@@ -0,0 +1,99 @@
+package confetti_test
+
+import (
+	"fmt"
+
+	"github.com/alexaandru/confetti"
+)
+
+type MergeConfig struct {
+	Host    string
+	Port    int
+	Debug   bool
+	Hosts   []string
+	Plugins []string `merge:"append"`
+	Peers   []struct {
+		Addr   string
+		Weight int
+	} `merge:"deep"`
+	Labels map[string]string
+	Limits map[string]int `merge:"replace"`
+	DB     *struct {
+		User, Pass string
+	}
+}
+
+func ExampleWithMerge() {
+	base := `{
+		"Host": "localhost", "Port": 8080, "Debug": true,
+		"Hosts": ["a", "b"], "Plugins": ["auth"],
+		"Peers": [{"Addr": "p1", "Weight": 1}, {"Addr": "p2", "Weight": 1}],
+		"Labels": {"team": "core", "tier": "1"}, "Limits": {"cpu": 2, "mem": 512},
+		"DB": {"User": "admin", "Pass": "secret"}
+	}`
+	overlay := `{
+		"Port": 9090, "Debug": null,
+		"Hosts": ["c"], "Plugins": ["metrics"],
+		"Peers": [{}, {"Weight": 5}],
+		"Labels": {"tier": null, "env": "prod"}, "Limits": {"cpu": 4},
+		"DB": {"Pass": "s3cr3t"}
+	}`
+
+	cfg := &MergeConfig{}
+	err := confetti.Load(cfg, confetti.WithMerge(), confetti.WithJSON([]byte(base)), confetti.WithJSON([]byte(overlay)))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port, cfg.Debug)
+	fmt.Println(cfg.Hosts, cfg.Plugins, cfg.Peers)
+	fmt.Println(cfg.Labels, cfg.Limits, *cfg.DB)
+	// Output:
+	// localhost 9090 false
+	// [c] [auth metrics] [{p1 1} {p2 5}]
+	// map[env:prod team:core] map[cpu:4] {admin s3cr3t}
+}
+
+func ExampleWithMerge_errors() {
+	cfg := &MergeConfig{}
+
+	fmt.Println(confetti.Load(cfg, confetti.WithMerge(), confetti.WithJSON([]byte(`{"DB":{"User":1}}`))))
+	fmt.Println(confetti.Load(cfg, confetti.WithMerge(), confetti.WithErrOnUnknown(), confetti.WithJSON([]byte(`{"Hosts":["a"],"Unknown":1}`))))
+	fmt.Println(cfg.Hosts)
+	// Output:
+	// DB.User: json: cannot unmarshal number into Go value of type string
+	// unknown fields in config: json: unknown field "Unknown"
+	// [a]
+}
+
+type MergeCommon struct {
+	Region string
+	Port   int
+}
+
+type MergeEmbedded struct {
+	*MergeCommon
+	Port int // Hides MergeCommon.Port, like with encoding/json.
+}
+
+func ExampleWithMerge_embedded() {
+	prov := confetti.Provenance{}
+	cfg := &MergeEmbedded{}
+
+	err := confetti.Load(cfg, confetti.WithMerge(), confetti.WithProvenance(&prov),
+		confetti.WithJSON([]byte(`{"Region": "eu-west-1", "Port": 8080}`)))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Region, cfg.Port, cfg.MergeCommon.Port, prov.Source("MergeCommon.Region"))
+
+	if err = confetti.Load(cfg, confetti.WithMergePatch([]byte(`{"Region": "us-east-2"}`))); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Region, cfg.Port)
+	// Output:
+	// eu-west-1 8080 0 json:[]byte
+	// us-east-2 8080
+}
@@ -0,0 +1,116 @@
+package confetti_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alexaandru/confetti"
+)
+
+type PatchConfig struct {
+	Host   string
+	Port   int
+	Hosts  []string
+	Labels map[string]string
+	Token  confetti.Secret[string]
+}
+
+func ExampleWithMergePatch() {
+	base := `{"Host": "localhost", "Port": 8080, "Hosts": ["a", "b"], "Labels": {"team": "core", "tier": "1"}, "Token": "s3cr3t"}`
+	patch := `{"Port": 9090, "Hosts": ["c"], "Labels": {"tier": null, "env": "prod"}}`
+
+	cfg := &PatchConfig{}
+	if err := confetti.Load(cfg, confetti.WithJSON([]byte(base)), confetti.WithMergePatch([]byte(patch))); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port, cfg.Hosts, cfg.Labels, cfg.Token.Value())
+	// Output:
+	// localhost 9090 [c] map[env:prod team:core] s3cr3t
+}
+
+func ExampleWithJSONPatch() {
+	base := `{"Host": "localhost", "Port": 8080, "Hosts": ["a", "b"], "Labels": {"team": "core", "tier": "1"}}`
+	patch := `[
+		{"op": "test", "path": "/Host", "value": "localhost"},
+		{"op": "replace", "path": "/Port", "value": 9090},
+		{"op": "add", "path": "/Hosts/-", "value": "c"},
+		{"op": "remove", "path": "/Hosts/0"},
+		{"op": "move", "from": "/Labels/tier", "path": "/Labels/level"}
+	]`
+
+	cfg := &PatchConfig{}
+	if err := confetti.Load(cfg, confetti.WithJSON([]byte(base)), confetti.WithJSONPatch([]byte(patch))); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port, cfg.Hosts, cfg.Labels)
+	// Output:
+	// localhost 9090 [b c] map[level:1 team:core]
+}
+
+func ExampleWithJSONPatch_errors() {
+	cfg := &PatchConfig{Host: "localhost"}
+
+	err := confetti.Load(cfg, confetti.WithJSONPatch([]byte(`[{"op": "test", "path": "/Host", "value": "example.com"}]`)))
+	fmt.Println(errors.Is(err, confetti.ErrPatchFailed))
+	fmt.Println(err)
+	fmt.Println(confetti.Load(cfg, confetti.WithJSONPatch([]byte(`[{"op": "replace", "path": "/Missing", "value": 1}]`))))
+	fmt.Println(confetti.Load(cfg, confetti.WithErrOnUnknown(), confetti.WithMergePatch([]byte(`{"Unknown": 1}`))))
+	// Output:
+	// true
+	// patch failed: operation 0 (test /Host): test failed
+	// patch failed: operation 0 (replace /Missing): path not found: "Missing"
+	// unknown fields in config: json: unknown field "Unknown"
+}
+
+func ExampleWithJSONPatch_secrets() {
+	type DB struct {
+		Host string
+		Pass confetti.Secret[string]
+	}
+
+	type Config struct {
+		DBs   []DB
+		Pass  confetti.Secret[string]
+		Other string
+	}
+
+	base := `{"DBs": [{"Host": "a", "Pass": "p1"}, {"Host": "b", "Pass": "p2"}], "Pass": "s3cr3t"}`
+	patch := `[
+		{"op": "test", "path": "/DBs/0/Pass", "value": "p1"},
+		{"op": "replace", "path": "/DBs/1/Host", "value": "c"},
+		{"op": "copy", "from": "/Pass", "path": "/Other"}
+	]`
+
+	cfg := &Config{}
+	if err := confetti.Load(cfg, confetti.WithJSON([]byte(base)), confetti.WithJSONPatch([]byte(patch))); err != nil {
+		panic(err)
+	}
+
+	for _, db := range cfg.DBs {
+		fmt.Println(db.Host, db.Pass.Value())
+	}
+
+	fmt.Println(cfg.Pass.Value(), cfg.Other)
+	// Output:
+	// a p1
+	// c p2
+	// s3cr3t s3cr3t
+}
+
+func ExampleWithJSONPatch_omitempty() {
+	type Config struct {
+		Host string `json:"host,omitempty"`
+		Port int    `json:"port,omitempty"`
+	}
+
+	cfg := &Config{Host: "localhost"}
+	if err := confetti.Load(cfg, confetti.WithJSONPatch([]byte(`[{"op": "replace", "path": "/port", "value": 8080}]`))); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port)
+	// Output:
+	// localhost 8080
+}
@@ -0,0 +1,52 @@
+package confetti_test
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+func ExampleWithProvenance() {
+	os.Setenv("MYAPP_PROV_PORT", "9090")
+
+	cfg := &struct {
+		Host   string `default:"localhost"`
+		Port   int
+		Debug  bool
+		Labels map[string]string
+		Nested struct{ Value string }
+	}{}
+
+	var prov confetti.Provenance
+
+	err := confetti.Load(cfg,
+		confetti.WithProvenance(&prov),
+		confetti.WithJSON([]byte(`{"Port":8080,"Labels":{"team":"core"},"Nested":{"Value":"foo"}}`)),
+		confetti.WithEnv("MYAPP_PROV"))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(prov)
+	fmt.Println(prov.Source("Labels.team"), prov.Source("Debug") == "")
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+	logger.Info("config loaded", "provenance", prov)
+	// Output:
+	// Host: default
+	// Labels: json:[]byte
+	// Nested.Value: json:[]byte
+	// Port: env:MYAPP_PROV_PORT
+	// json:[]byte true
+	// level=INFO msg="config loaded" provenance.Host=default provenance.Labels=json:[]byte provenance.Nested.Value=json:[]byte provenance.Port=env:MYAPP_PROV_PORT
+}
@@ -0,0 +1,46 @@
+package confetti_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+type RequiredConfig struct {
+	Host    string `required:"true"`
+	Port    int    `env:",required" json:"port"`
+	Retries int    `required:"true"`
+	DB      struct {
+		Password string `env:"DB_PASS,required"`
+	}
+}
+
+func ExampleLoad_required() {
+	os.Setenv("REQ1_RETRIES", "0") // zero, but explicitly set
+
+	cfg := &RequiredConfig{}
+	err := confetti.Load(cfg, confetti.WithJSON([]byte(`{"port":8080}`)), confetti.WithEnv("REQ1"))
+
+	fmt.Println(errors.Is(err, confetti.ErrMissingRequired))
+	fmt.Println(err)
+	// Output:
+	// true
+	// missing required fields: Host (env REQ1_HOST, json Host); DB.Password (env DB_PASS, json DB.Password)
+}
+
+func ExampleLoad_required_satisfied() {
+	os.Setenv("REQ2_HOST", "localhost")
+	os.Setenv("REQ2_PORT", "8080")
+	os.Setenv("DB_PASS", "secret")
+
+	cfg := &RequiredConfig{Retries: 3} // pre-populated
+	err := confetti.Load(cfg, confetti.WithEnv("REQ2"))
+
+	fmt.Printf("Host=%s Port=%d Retries=%d DB.Password=%s\n", cfg.Host, cfg.Port, cfg.Retries, cfg.DB.Password)
+	fmt.Println(err)
+	// Output:
+	// Host=localhost Port=8080 Retries=3 DB.Password=secret
+	// <nil>
+}
@@ -0,0 +1,126 @@
+package confetti_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexaandru/confetti"
+)
+
+type SchemaConfig struct {
+	Host     string                  `json:"host" required:"true" desc:"The host to listen on" validate:"regexp=^[a-z.]+$"`
+	Port     int                     `json:"port" default:"8080" validate:"min=1,max=65535"`
+	Timeout  time.Duration           `json:"timeout,omitempty" default:"30s"`
+	Mode     string                  `json:"mode" default:"dev" validate:"oneof=dev prod"`
+	Peers    []string                `json:"peers" validate:"omitempty,min=2,hostport"`
+	Labels   map[string]string       `json:"labels"`
+	Password confetti.Secret[string] `json:"password"`
+	DB       *struct {
+		User string `json:"user" env:",required"`
+	} `json:"db"`
+	Internal string `json:"-"`
+}
+
+func ExampleJSONSchema() {
+	schema, err := confetti.JSONSchema(SchemaConfig{})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(schema))
+	// Output:
+	// {
+	//   "$schema": "https://json-schema.org/draft/2020-12/schema",
+	//   "additionalProperties": false,
+	//   "properties": {
+	//     "db": {
+	//       "additionalProperties": false,
+	//       "properties": {
+	//         "user": {
+	//           "type": "string"
+	//         }
+	//       },
+	//       "required": [
+	//         "user"
+	//       ],
+	//       "type": "object"
+	//     },
+	//     "host": {
+	//       "description": "The host to listen on",
+	//       "pattern": "^[a-z.]+$",
+	//       "type": "string"
+	//     },
+	//     "labels": {
+	//       "additionalProperties": {
+	//         "type": "string"
+	//       },
+	//       "type": "object"
+	//     },
+	//     "mode": {
+	//       "default": "dev",
+	//       "enum": [
+	//         "dev",
+	//         "prod"
+	//       ],
+	//       "type": "string"
+	//     },
+	//     "password": {
+	//       "type": "string",
+	//       "writeOnly": true
+	//     },
+	//     "peers": {
+	//       "anyOf": [
+	//         {
+	//           "maxItems": 0
+	//         },
+	//         {
+	//           "minItems": 2
+	//         }
+	//       ],
+	//       "items": {
+	//         "pattern": ":[0-9]{1,5}$",
+	//         "type": "string"
+	//       },
+	//       "type": "array"
+	//     },
+	//     "port": {
+	//       "default": 8080,
+	//       "maximum": 65535,
+	//       "minimum": 1,
+	//       "type": "integer"
+	//     },
+	//     "timeout": {
+	//       "default": "30s",
+	//       "pattern": "^[-+]?(0|(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$",
+	//       "type": [
+	//         "string",
+	//         "integer"
+	//       ]
+	//     }
+	//   },
+	//   "required": [
+	//     "host"
+	//   ],
+	//   "title": "SchemaConfig",
+	//   "type": "object"
+	// }
+}
+
+func ExampleJSONSchema_errors() {
+	_, err := confetti.JSONSchema(struct {
+		Port int `validate:"positive"`
+	}{})
+	fmt.Println(err)
+
+	_, err = confetti.JSONSchema(struct {
+		Port int `default:"eighty"`
+	}{})
+	fmt.Println(err)
+
+	_, err = confetti.JSONSchema(42)
+	fmt.Println(err)
+	// Output:
+	// Port: unknown validation rule "positive"
+	// default Port: strconv.ParseInt: parsing "eighty": invalid syntax
+	// config must be a struct or a pointer to one (got int)
+}
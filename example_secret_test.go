@@ -0,0 +1,101 @@
+package confetti_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/alexaandru/confetti"
+)
+
+type SecretConfig struct {
+	Host     string
+	Password confetti.Secret[string]
+	PIN      confetti.Secret[int]
+	APIKey   string `secret:"true"`
+	DB       struct {
+		User string
+		Pass string
+	}
+	Peers []struct {
+		Addr  string
+		Token string `secret:"true"`
+	}
+}
+
+func ExampleSecret() {
+	os.Setenv("MYAPP_SECRET_PASSWORD", "hunter2")
+	os.Setenv("MYAPP_SECRET_PIN", "1234")
+
+	cfg := &SecretConfig{}
+	if err := confetti.Load(cfg, confetti.WithEnv("MYAPP_SECRET")); err != nil {
+		panic(err)
+	}
+
+	b, _ := json.Marshal(cfg.Password)
+
+	fmt.Println(cfg.Password.Value(), cfg.PIN.Value())
+	fmt.Printf("%v %+v %#v %s %d %q %s\n", cfg.Password, cfg.Password, cfg.Password, cfg.Password, cfg.PIN, cfg.Password, b)
+	slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})).Info("loaded", "password", cfg.Password)
+	// Output:
+	// hunter2 1234
+	// [REDACTED] [REDACTED] [REDACTED] [REDACTED] [REDACTED] [REDACTED] "[REDACTED]"
+	// level=INFO msg=loaded password=[REDACTED]
+}
+
+func ExampleRedacted() {
+	data := `{"Host":"localhost","Password":"hunter2","APIKey":"abc","DB":{"User":"admin","Pass":"s3cr3t"},"Peers":[{"Addr":"a:1","Token":"t1"}]}`
+	ssmValue := `{"DB":{"Pass":"s3cr3t"}}`
+
+	var prov confetti.Provenance
+
+	cfg := &SecretConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithProvenance(&prov),
+		confetti.WithMockedSSM(&mockSSM{value: ssmValue}),
+		confetti.WithJSON([]byte(data)),
+		confetti.WithSSM("/myapp/db"))
+	if err != nil {
+		panic(err)
+	}
+
+	b, _ := json.Marshal(confetti.Redacted(cfg, prov))
+
+	fmt.Printf("%+v\n", *confetti.Redacted(cfg, prov))
+	fmt.Println(string(b))
+	fmt.Println(cfg.APIKey, cfg.DB.Pass, cfg.Peers[0].Token)
+	// Output:
+	// {Host:localhost Password:[REDACTED] PIN:[REDACTED] APIKey:[REDACTED] DB:{User:admin Pass:[REDACTED]} Peers:[{Addr:a:1 Token:[REDACTED]}]}
+	// {"Host":"localhost","Password":"[REDACTED]","PIN":"[REDACTED]","APIKey":"[REDACTED]","DB":{"User":"admin","Pass":"[REDACTED]"},"Peers":[{"Addr":"a:1","Token":"[REDACTED]"}]}
+	// abc s3cr3t t1
+}
+
+func ExampleRedacted_maps() {
+	type Creds struct {
+		User string
+		Pass string `secret:"true"`
+	}
+
+	cfg := struct {
+		Creds  map[string]Creds
+		Tokens map[string]string `secret:"true"`
+	}{
+		Creds:  map[string]Creds{"a": {User: "u", Pass: "p"}},
+		Tokens: map[string]string{"gh": "t1"},
+	}
+
+	fmt.Printf("%+v\n", confetti.Redacted(cfg))
+	fmt.Println(cfg.Creds["a"].Pass, cfg.Tokens["gh"])
+	// Output:
+	// {Creds:map[a:{User:u Pass:[REDACTED]}] Tokens:map[gh:[REDACTED]]}
+	// p t1
+}
@@ -0,0 +1,91 @@
+package confetti_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexaandru/confetti"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+type mockSecretsManager struct {
+	versions map[string]string // by version stage, "" being AWSCURRENT.
+	binary   bool
+}
+
+func ExampleLoad_secrets_manager() {
+	mock := &mockSecretsManager{versions: map[string]string{
+		"":            `{"Host":"smhost","Port":9000,"Nested":{"Value":"current","Deep":{"Unknown":"unknown"}}}`,
+		"AWSPREVIOUS": `{"Host":"smhost","Port":9000,"Nested":{"Value":"previous"}}`,
+	}}
+
+	cfg := &ExampleConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithMockedSecretsManager(mock),
+		confetti.WithSecretsManager("prod/myapp", "us-east-1"),
+	)
+	fmt.Printf("Host=%s Port=%d Nested.Value=%s\n", cfg.Host, cfg.Port, cfg.Nested.Value)
+	fmt.Println(err)
+
+	err = confetti.Load(cfg,
+		confetti.WithMockedSecretsManager(mock),
+		confetti.WithSecretsManager("prod/myapp", "us-east-1", "", "AWSPREVIOUS"),
+	)
+	fmt.Printf("Nested.Value=%s\n", cfg.Nested.Value)
+	fmt.Println(err)
+
+	mock.binary = true
+	err = confetti.Load(cfg,
+		confetti.WithErrOnUnknown(),
+		confetti.WithMockedSecretsManager(mock),
+		confetti.WithSecretsManager("prod/myapp"),
+	)
+	fmt.Printf("Nested.Value=%s\n", cfg.Nested.Value)
+	fmt.Println(err)
+	// Output:
+	// Host=smhost Port=9000 Nested.Value=current
+	// <nil>
+	// Nested.Value=previous
+	// <nil>
+	// Nested.Value=current
+	// unknown fields in config: json: unknown field "Unknown"
+}
+
+func ExampleLoad_secrets_manager_error() {
+	cfg := &ExampleConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithMockedSecretsManager(&mockSecretsManager{}),
+		confetti.WithSecretsManager("missing"),
+	)
+	fmt.Println(err)
+
+	err = confetti.Load(cfg,
+		confetti.WithMockedSecretsManager(&mockSecretsManager{versions: map[string]string{"": ""}}),
+		confetti.WithSecretsManager("empty"),
+	)
+	fmt.Println(err)
+	// Output:
+	// failed to get secret missing: ResourceNotFoundException
+	// secret empty not found or has no value
+}
+
+func (m *mockSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	stage := ""
+	if params.VersionStage != nil {
+		stage = *params.VersionStage
+	}
+
+	value, ok := m.versions[stage]
+
+	switch {
+	case !ok:
+		return nil, errors.New("ResourceNotFoundException")
+	case value == "":
+		return &secretsmanager.GetSecretValueOutput{}, nil
+	case m.binary:
+		return &secretsmanager.GetSecretValueOutput{SecretBinary: []byte(value)}, nil
+	default:
+		return &secretsmanager.GetSecretValueOutput{SecretString: &value}, nil
+	}
+}
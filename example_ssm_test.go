@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alexaandru/confetti"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
@@ -11,7 +15,9 @@ import (
 )
 
 type mockSSM struct {
-	value string
+	params map[string]string // for GetParametersByPath (served 2 per page) and GetParameters.
+	value  string
+	calls  int
 }
 
 func ExampleLoad_ssm() {
@@ -63,7 +69,127 @@ func ExampleLoad_ssm_error() {
 	// Error: failed to get SSM parameter fail: mock SSM error
 }
 
+func ExampleLoadContext_ssm_timeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	cfg := &ExampleConfig{}
+	err := confetti.LoadContext(ctx, cfg,
+		confetti.WithMockedSSM(&mockSSM{value: "block"}),
+		confetti.WithSSM("slow", "us-east-1"),
+	)
+	fmt.Printf("Error: %v\n", err)
+	fmt.Println(errors.Is(err, context.DeadlineExceeded))
+	// Output:
+	// Error: ssm:slow: failed to get SSM parameter slow: context deadline exceeded
+	// true
+}
+
+func ExampleLoad_ssm_path() {
+	type SSMPathConfig struct {
+		Host string
+		Port int
+		DB   struct {
+			User       string
+			Password   string `ssm:"pass"`
+			MaxRetries int
+		}
+		Hosts  []string
+		Labels map[string]string
+	}
+
+	params := map[string]string{
+		"/myapp/prod/host":              "ssmhost",
+		"/myapp/prod/port":              "9000",
+		"/myapp/prod/db/user":           "admin",
+		"/myapp/prod/db/pass":           "s3cr3t",
+		"/myapp/prod/db/max-retries":    "3",
+		"/myapp/prod/hosts":             "a,b",
+		"/myapp/prod/labels/team":       "core",
+		"/myapp/prod/labels/tier":       "backend",
+		"/myapp/prod/unknown/parameter": "x",
+	}
+
+	cfg := &SSMPathConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithMockedSSM(&mockSSM{params: params}),
+		confetti.WithSSMPath("/myapp/prod/"),
+	)
+
+	fmt.Printf("Host=%s Port=%d\n", cfg.Host, cfg.Port)
+	fmt.Printf("DB.User=%s DB.Password=%s DB.MaxRetries=%d\n", cfg.DB.User, cfg.DB.Password, cfg.DB.MaxRetries)
+	fmt.Printf("Hosts=%v Labels=%v\n", cfg.Hosts, cfg.Labels)
+	fmt.Println(err)
+
+	err = confetti.Load(cfg,
+		confetti.WithErrOnUnknown(),
+		confetti.WithMockedSSM(&mockSSM{params: params}),
+		confetti.WithSSMPath("/myapp/prod"),
+	)
+	fmt.Println(err)
+
+	err = confetti.Load(cfg,
+		confetti.WithMockedSSM(&mockSSM{value: "error: mock SSM error"}),
+		confetti.WithSSMPath("/myapp/prod"),
+	)
+	fmt.Println(err)
+	// Output:
+	// Host=ssmhost Port=9000
+	// DB.User=admin DB.Password=s3cr3t DB.MaxRetries=3
+	// Hosts=[a b] Labels=map[team:core tier:backend]
+	// <nil>
+	// unknown fields in config: unknown SSM parameters: [/myapp/prod/unknown/parameter]
+	// failed to get SSM parameters by path /myapp/prod: mock SSM error
+}
+
+func ExampleLoad_ssm_refs() {
+	type SSMRefsConfig struct {
+		Host string
+		DB   struct {
+			User     string `ssm:"/prod/db/user"`
+			Password string `ssm:"/prod/db/password"`
+		}
+		Ports   []int         `ssm:"/prod/ports"`
+		Timeout time.Duration `ssm:"/prod/timeout"`
+	}
+
+	params := map[string]string{
+		"/prod/db/user":     "admin",
+		"/prod/db/password": "s3cr3t",
+		"/prod/ports":       "80,443",
+		"/prod/timeout":     "5s",
+	}
+
+	mock := &mockSSM{params: params}
+	cfg := &SSMRefsConfig{}
+	err := confetti.Load(cfg,
+		confetti.WithJSON([]byte(`{"Host":"jsonhost","DB":{"User":"jsonuser"}}`)),
+		confetti.WithMockedSSM(mock),
+		confetti.WithSSMRefs(),
+	)
+
+	fmt.Printf("Host=%s DB.User=%s DB.Password=%s\n", cfg.Host, cfg.DB.User, cfg.DB.Password)
+	fmt.Printf("Ports=%v Timeout=%s Calls=%d\n", cfg.Ports, cfg.Timeout, mock.calls)
+	fmt.Println(err)
+
+	delete(params, "/prod/ports")
+	delete(params, "/prod/timeout")
+
+	err = confetti.Load(cfg, confetti.WithMockedSSM(mock), confetti.WithSSMRefs())
+	fmt.Println(err)
+	// Output:
+	// Host=jsonhost DB.User=admin DB.Password=s3cr3t
+	// Ports=[80 443] Timeout=5s Calls=1
+	// <nil>
+	// SSM parameters not found or have no value: [/prod/ports /prod/timeout]
+}
+
 func (m *mockSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if m.value == "block" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
 	if len(m.value) > 7 && m.value[:7] == "error: " {
 		return nil, errors.New(m.value[7:])
 	}
@@ -74,3 +200,59 @@ func (m *mockSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInpu
 
 	return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: &m.value}}, nil
 }
+
+func (m *mockSSM) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	if len(m.value) > 7 && m.value[:7] == "error: " {
+		return nil, errors.New(m.value[7:])
+	}
+
+	names := []string{}
+
+	for name := range m.params {
+		if strings.HasPrefix(name, *params.Path+"/") {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+
+	out := &ssm.GetParametersByPathOutput{}
+
+	for _, name := range names[start:min(start+2, len(names))] {
+		value := m.params[name]
+		out.Parameters = append(out.Parameters, ssmtypes.Parameter{Name: &name, Value: &value})
+	}
+
+	if start+2 < len(names) {
+		next := strconv.Itoa(start + 2)
+		out.NextToken = &next
+	}
+
+	return out, nil
+}
+
+func (m *mockSSM) GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	if len(params.Names) > 10 {
+		return nil, errors.New("too many parameters")
+	}
+
+	m.calls++
+	out := &ssm.GetParametersOutput{}
+
+	for _, name := range params.Names {
+		value, ok := m.params[name]
+		if !ok {
+			out.InvalidParameters = append(out.InvalidParameters, name)
+			continue
+		}
+
+		out.Parameters = append(out.Parameters, ssmtypes.Parameter{Name: &name, Value: &value})
+	}
+
+	return out, nil
+}
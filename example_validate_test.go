@@ -0,0 +1,68 @@
+package confetti_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexaandru/confetti"
+)
+
+type ValidatedConfig struct {
+	Port     int           `validate:"min=1,max=65535"`
+	Env      string        `validate:"oneof=dev staging prod"`
+	Endpoint string        `validate:"omitempty,url"`
+	Addr     string        `validate:"hostport"`
+	Name     string        `validate:"min=3,regexp=^[a-z]{1,3}(-[a-z]+)*$"`
+	Timeout  time.Duration `validate:"max=1m"`
+	Peers    []string      `validate:"max=2,hostport"`
+	DB       DBConfig
+}
+
+type DBConfig struct {
+	User, Password string
+}
+
+func (c DBConfig) Validate() error {
+	if c.User != "" && c.Password == "" {
+		return errors.New("password is required when user is set")
+	}
+
+	return nil
+}
+
+func ExampleLoad_validate() {
+	cfg := &ValidatedConfig{}
+	err := confetti.Load(cfg, confetti.WithJSON([]byte(`{
+		"Port": 70000, "Env": "qa", "Addr": "localhost", "Name": "my-app",
+		"Timeout": 120000000000, "Peers": ["a:1", "b"], "DB": {"User": "admin"}
+	}`)))
+
+	fmt.Println(errors.Is(err, confetti.ErrInvalidConfig))
+	fmt.Println(err)
+
+	err = confetti.Load(cfg, confetti.WithJSON([]byte(`{
+		"Port": 8080, "Env": "prod", "Endpoint": "https://example.com", "Addr": "localhost:80",
+		"Name": "my-app", "Timeout": 1000000000, "Peers": ["a:1", "b:2"], "DB": {"User": "admin", "Password": "x"}
+	}`)))
+
+	fmt.Println(err)
+	// Output:
+	// true
+	// invalid config: Port: value must be at most 65535; Env: must be one of [dev staging prod]; Addr: must be a host:port pair; Timeout: value must be at most 1m; Peers[1]: must be a host:port pair; DB: password is required when user is set
+	// <nil>
+}
+
+type EmbeddedValidatedConfig struct {
+	DBConfig     // Its Validate method is promoted, so it is called once.
+	Port     int `validate:"min=1"`
+}
+
+func ExampleLoad_validateEmbedded() {
+	cfg := &EmbeddedValidatedConfig{}
+	err := confetti.Load(cfg, confetti.WithJSON([]byte(`{"User": "admin", "Port": 0}`)))
+
+	fmt.Println(err)
+	// Output:
+	// invalid config: Port: value must be at least 1; password is required when user is set
+}
@@ -0,0 +1,149 @@
+package confetti_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alexaandru/confetti"
+)
+
+type WatchConfig struct {
+	Host   string
+	Port   int `validate:"max=65535"`
+	Nested struct{ Value string }
+}
+
+type WatchMapConfig struct {
+	Labels map[string]string
+	Limits *WatchLimits
+}
+
+type WatchLimits struct{ CPU int }
+
+func ExampleWatch() {
+	file := "test_watch.json"
+	if err := os.WriteFile(file, []byte(`{"Host":"localhost","Port":8080}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &WatchConfig{}
+
+	w, err := confetti.Watch(ctx, cfg, confetti.WithJSON(file), confetti.WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		panic(err)
+	}
+
+	changes := make(chan confetti.Change[WatchConfig], 1)
+	w.Subscribe(func(c confetti.Change[WatchConfig]) { changes <- c })
+
+	fmt.Println(w.Config().Host, w.Config().Port)
+
+	if err = os.WriteFile(file, []byte(`{"Host":"localhost","Port":9090,"Nested":{"Value":"foo"}}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+
+	c := <-changes
+	fmt.Println(c.Old.Port, c.New.Port, c.Paths, w.Config().Port, cfg.Port)
+	// Output:
+	// localhost 8080
+	// 8080 9090 [Port Nested.Value] 9090 8080
+}
+
+func ExampleWatch_prefilled() {
+	file := "test_watch_prefilled.json"
+	if err := os.WriteFile(file, []byte(`{"Labels":{"a":"1"}}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &WatchMapConfig{Labels: map[string]string{"base": "x"}, Limits: &WatchLimits{CPU: 1}}
+
+	w, err := confetti.Watch(ctx, cfg, confetti.WithJSON(file), confetti.WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		panic(err)
+	}
+
+	changes := make(chan confetti.Change[WatchMapConfig], 1)
+	w.Subscribe(func(c confetti.Change[WatchMapConfig]) { changes <- c })
+
+	first := w.Config()
+
+	if err = os.WriteFile(file, []byte(`{"Labels":{"b":"2"},"Limits":{"CPU":2}}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+
+	c := <-changes
+	fmt.Println(first.Labels, first.Limits.CPU)
+	fmt.Println(c.New.Labels, c.New.Limits.CPU, c.Paths)
+	// Output:
+	// map[a:1 base:x] 1
+	// map[b:2 base:x] 2 [Labels Limits.CPU]
+}
+
+func ExampleWatcher_Reload() {
+	file := "test_reload.json"
+	if err := os.WriteFile(file, []byte(`{"Port":8080}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := confetti.Watch(ctx, &WatchConfig{}, confetti.WithJSON(file), confetti.WithPollInterval(time.Hour))
+	if err != nil {
+		panic(err)
+	}
+
+	if err = os.WriteFile(file, []byte(`{"Port":70000}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+
+	fmt.Println(w.Reload())
+	fmt.Println(w.Config().Port, w.Err())
+	// Output:
+	// invalid config: Port: value must be at most 65535
+	// 8080 invalid config: Port: value must be at most 65535
+}
+
+func ExampleWatch_provenance() {
+	file := "test_watch_provenance.json"
+	if err := os.WriteFile(file, []byte(`{"Host":"localhost"}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var prov confetti.Provenance
+
+	w, err := confetti.Watch(ctx, &WatchConfig{}, confetti.WithProvenance(&prov), confetti.WithJSON(file),
+		confetti.WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		panic(err)
+	}
+
+	changes := make(chan confetti.Change[WatchConfig], 1)
+	w.Subscribe(func(c confetti.Change[WatchConfig]) { changes <- c })
+
+	if err = os.WriteFile(file, []byte(`{"Host":"localhost","Port":9090}`), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+
+	c := <-changes
+	fmt.Println(prov.Source("Host"), prov.Source("Port") == "")
+	fmt.Println(c.Provenance.Source("Host"), c.Provenance.Source("Port"))
+	// Output:
+	// json:test_watch_provenance.json true
+	// json:test_watch_provenance.json json:test_watch_provenance.json
+}
@@ -0,0 +1,186 @@
+package confetti
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// flagsLoader loads config from command line flags, registered for each config field.
+type flagsLoader struct {
+	fs   *flag.FlagSet
+	args []string
+}
+
+// flagValue is the flag.Value of a config field, reached from root by following index
+// (as in reflect.Value.FieldByIndex) and allocating any nil pointer to struct on the way,
+// once the flag is set.
+type flagValue struct {
+	root  reflect.Value
+	index []int
+	typ   reflect.Type
+	path  string
+	name  string
+	dec   decoder
+	opts  *Options
+}
+
+func (f flagsLoader) Load(config any, opts *Options) (err error) {
+	fs, args := f.fs, f.args
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}.withCustom(opts)
+	root := reflect.ValueOf(config).Elem()
+
+	registerFlags(fs, root, root.Type(), nil, "", "", dec, opts, map[reflect.Type]bool{})
+
+	return fs.Parse(args)
+}
+
+func (f flagsLoader) String() string {
+	return "flags"
+}
+
+// registerFlags registers a flag in fs for each field of struct type t (found at index within
+// root), descending into nested structs. Flags already registered (e.g. by a previous Load
+// call) are retargeted to root, so that the same flag set can be used repeatedly.
+// The seen struct types are skipped, so that recursive types are only descended into once.
+func registerFlags(fs *flag.FlagSet, root reflect.Value, t reflect.Type, index []int, prefix, path string,
+	dec decoder, opts *Options, seen map[reflect.Type]bool,
+) {
+	if seen[t] {
+		return
+	}
+
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("flag") == "-" {
+			continue
+		}
+
+		name, fieldPath, fieldIndex := flagName(field, prefix), joinPath(path, field.Name), append(slices.Clone(index), i)
+
+		if dec.nested(field.Type) {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			registerFlags(fs, root, ft, fieldIndex, name, fieldPath, dec, opts, seen)
+
+			continue
+		}
+
+		if !flaggable(field.Type, dec) {
+			continue
+		}
+
+		fv := &flagValue{root: root, index: fieldIndex, typ: field.Type, path: fieldPath, name: name, dec: dec, opts: opts}
+
+		if f := fs.Lookup(name); f != nil {
+			if old, ok := f.Value.(*flagValue); ok {
+				*old = *fv
+			}
+
+			continue
+		}
+
+		fs.Var(fv, name, field.Tag.Get("usage"))
+	}
+}
+
+// flagName returns the flag name of field (or the prefix of its own fields, for nested
+// structs): either its `flag` tag or its kebab-case name, prefixed.
+func flagName(field reflect.StructField, prefix string) string {
+	if name := field.Tag.Get("flag"); name != "" {
+		return name
+	}
+
+	name := strings.ReplaceAll(strings.ToLower(camelToUpperSnake(field.Name)), "_", "-")
+	if prefix != "" {
+		name = prefix + "-" + name
+	}
+
+	return name
+}
+
+// flaggable reports whether fields of type t can be set from a flag.
+func flaggable(t reflect.Type, dec decoder) bool {
+	switch t.Kind() { //nolint:exhaustive // ok
+	case reflect.Slice, reflect.Map:
+		return true
+	default:
+		return dec.canDecode(t)
+	}
+}
+
+// field returns the value of the field, allocating any nil pointer to struct
+// on the way if alloc is true, or an invalid value if it cannot be reached.
+func (f *flagValue) field(alloc bool) reflect.Value {
+	v := f.root
+
+	for i, idx := range f.index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(idx)
+	}
+
+	return v
+}
+
+// String returns the current value of the field, shown as the default value in usage messages.
+func (f *flagValue) String() string {
+	if f == nil || !f.root.IsValid() {
+		return ""
+	}
+
+	v := f.field(false)
+	if !v.IsValid() || v.IsZero() {
+		return ""
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+func (f *flagValue) Set(val string) error {
+	if err := f.dec.setValue(f.field(true), val, "flag "+f.name); err != nil {
+		return err
+	}
+
+	f.opts.MarkSet(f.path, "flag:"+f.name)
+
+	return nil
+}
+
+// IsBoolFlag lets boolean flags be given without a value (e.g. -debug).
+func (f *flagValue) IsBoolFlag() bool {
+	t := f.typ
+
+	return t.Kind() == reflect.Bool || (t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Bool)
+}
@@ -0,0 +1,164 @@
+// Package remap converts generic documents, as decoded by third-party parsers (e.g. YAML, TOML),
+// into the JSON representation of a config struct, so that they can be loaded by confetti.WithJSON
+// and benefit from its strict mode and field tracking. The JSON loaders use it too, for durations.
+package remap
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ToJSON renames the keys of doc to the JSON keys of the fields of config they match, using the
+// given struct tag (e.g. "yaml", see field), and returns the result encoded as JSON. Duration strings
+// (e.g. "5s") meant for time.Duration fields are converted to nanoseconds. Keys not matching any
+// field are kept as they are, so that unknown fields can still be reported.
+func ToJSON(doc, config any, tag string) ([]byte, error) {
+	return json.Marshal(convert(doc, reflect.TypeOf(config), tag))
+}
+
+// convert returns v, with its keys renamed and durations converted for type t (which may be nil,
+// if not known). Maps with non-string keys (as YAML allows) get their keys formatted as strings.
+func convert(v any, t reflect.Type, tag string) any {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		return convertMap(x, t, tag)
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for k, e := range x {
+			m[fmt.Sprint(k)] = e
+		}
+
+		return convertMap(m, t, tag)
+	case []any:
+		var et reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			et = t.Elem()
+		}
+
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = convert(e, et, tag)
+		}
+
+		return out
+	case string:
+		if t == reflect.TypeFor[time.Duration]() {
+			if d, err := time.ParseDuration(x); err == nil {
+				return int64(d)
+			}
+		}
+	}
+
+	return v
+}
+
+func convertMap(m map[string]any, t reflect.Type, tag string) map[string]any {
+	out := make(map[string]any, len(m))
+
+	for k, e := range m {
+		var et reflect.Type
+
+		switch {
+		case t == nil:
+		case t.Kind() == reflect.Struct && !decodable(t):
+			if name, ft, ok := field(t, k, tag); ok {
+				k, et = name, ft
+			}
+		case t.Kind() == reflect.Map:
+			et = t.Elem()
+		}
+
+		out[k] = convert(e, et, tag)
+	}
+
+	return out
+}
+
+// field finds the field of struct type t matching key: the one named key by its tag (or, if it
+// has none, by its `json` tag or its Go name), preferring an exact match over a case-insensitive
+// one. It returns the JSON key encoding/json decodes into that field, and the field type.
+// Embedded untagged structs (and pointers to structs) are descended into, as encoding/json
+// promotes their fields.
+func field(t reflect.Type, key, tag string) (name string, ft reflect.Type, ok bool) {
+	return fieldIn(t, key, tag, map[reflect.Type]bool{})
+}
+
+// fieldIn implements field, skipping the seen struct types, so that recursive types
+// are only descended into once.
+func fieldIn(t reflect.Type, key, tag string, seen map[reflect.Type]bool) (name string, ft reflect.Type, ok bool) {
+	if seen[t] {
+		return
+	}
+
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		tagName, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		if tagName == "-" || jsonName == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+
+		if et := embeddedStruct(f); et != nil && tagName == "" && jsonName == "" {
+			if n, et, found := fieldIn(et, key, tag, seen); found {
+				return n, et, true
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		jsonName = cmp.Or(jsonName, f.Name)
+
+		if cmp.Or(tagName, jsonName) == key {
+			return jsonName, f.Type, true
+		}
+
+		if !ok && strings.EqualFold(cmp.Or(tagName, jsonName), key) {
+			name, ft, ok = jsonName, f.Type, true
+		}
+	}
+
+	return
+}
+
+// embeddedStruct returns the struct type of the embedded field f, if it is a struct or,
+// like with encoding/json, an exported pointer to one, or nil.
+func embeddedStruct(f reflect.StructField) reflect.Type {
+	switch {
+	case !f.Anonymous:
+	case f.Type.Kind() == reflect.Struct:
+		return f.Type
+	case f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct && f.IsExported():
+		return f.Type.Elem()
+	}
+
+	return nil
+}
+
+// decodable reports whether t decodes itself (e.g. time.Time), and so has no fields to map.
+func decodable(t reflect.Type) bool {
+	for _, it := range []reflect.Type{reflect.TypeFor[encoding.TextUnmarshaler](), reflect.TypeFor[json.Unmarshaler]()} {
+		if t.Implements(it) || reflect.PointerTo(t).Implements(it) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package remap
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Source is a document source, as accepted by confetti.WithJSON: a file path (string),
+// []byte, io.ReadSeeker or io.Reader. Files and io.ReadSeekers are (re)read on every Read.
+type Source struct {
+	// Name describes the source (e.g. the file path or "[]byte").
+	Name string
+
+	path string
+	data []byte
+	r    io.ReadSeeker
+	err  error
+}
+
+// NewSource returns the Source for src. The loader name (e.g. "WithYAML") is used in
+// the error reported (by Read) for unsupported types.
+func NewSource(src any, loader string) Source {
+	switch v := src.(type) {
+	case string:
+		return Source{Name: v, path: v}
+	case []byte:
+		return Source{Name: "[]byte", data: v}
+	case io.ReadSeeker:
+		return Source{Name: "io.ReadSeeker", r: v}
+	case io.Reader:
+		b, err := io.ReadAll(v)
+		return Source{Name: "io.Reader", data: b, err: err}
+	default:
+		return Source{err: fmt.Errorf("unsupported type for %s: %T", loader, src)}
+	}
+}
+
+// Read returns the content of the source.
+func (s Source) Read() ([]byte, error) {
+	switch {
+	case s.err != nil:
+		return nil, s.err
+	case s.path != "":
+		return os.ReadFile(s.path) //nolint:gosec // this is the whole point of the library.
+	case s.r != nil:
+		if _, err := s.r.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(s.r)
+	default:
+		return s.data, nil
+	}
+}
+
+// Path returns the file path of the source, or "" if it is not a file.
+func (s Source) Path() string {
+	return s.path
+}
@@ -0,0 +1,366 @@
+package confetti
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrUnresolvedRefs is returned (wrapped) when references cannot be resolved (see WithInterpolation).
+var ErrUnresolvedRefs = errors.New("unresolved references")
+
+type optsInterpolationLoader struct {
+	awsRegion, profile string
+}
+
+func (o optsInterpolationLoader) Load(_ any, opts *Options) (err error) {
+	opts.interpolation = &o
+	return
+}
+
+// WithInterpolation makes Load resolve the references in the string values of the config, after
+// all the loaders ran (and before the required fields check and the validation), so that they can
+// come from any source (e.g. JSON files, SSM JSON blobs, env vars):
+//   - ${NAME} is replaced with the value of the NAME env var;
+//   - ${ssm:/path} is replaced with the value of the /path SSM parameter;
+//   - ${REF:-default} is replaced with default if REF (either of the above) is unset or empty;
+//   - $${ is replaced with a literal ${.
+//
+// The values the references resolve to (and the defaults) can themselves hold references, which
+// are resolved too. Only string values are interpolated (including in slices, maps and Secret
+// fields). All the references that cannot be resolved, as well as the reference cycles, are
+// reported at once, in an ErrUnresolvedRefs error.
+//
+// Fields referencing SSM parameters are recorded as set by them (see WithProvenance and Redacted).
+// The optional region and profile arguments override the default AWS region/profile.
+func WithInterpolation(opts ...string) optsInterpolationLoader {
+	awsRegion, profile := DefaultAWSRegion, ""
+	if len(opts) > 0 {
+		awsRegion = opts[0]
+	}
+
+	if len(opts) > 1 {
+		profile = opts[1]
+	}
+
+	return optsInterpolationLoader{awsRegion: awsRegion, profile: profile}
+}
+
+// interpolator resolves the references in the string values of a config (see WithInterpolation).
+type interpolator struct {
+	optsInterpolationLoader
+	opts *Options
+	svc  SSMAPI
+
+	ssm      map[string]string      // the SSM parameters fetched so far,
+	missing  map[string]bool        // and those which could not be found.
+	cache    map[string]resolvedRef // the references resolved so far, with no problems.
+	problems []string               // the unresolved references and cycles, as reported.
+	path     string                 // the path of the field being interpolated,
+	ssmUsed  string                 // and the first SSM parameter its value references, if any.
+}
+
+// resolvedRef is the expanded value of a reference and the first SSM parameter it used, if any.
+type resolvedRef struct {
+	val, ssm string
+}
+
+// stringExpander is implemented by the values holding a string that should be interpolated,
+// despite being otherwise opaque (e.g. Secret).
+type stringExpander interface {
+	expandString(fn func(string) (string, error)) error
+}
+
+// interpolate resolves the references in the string values of struct v (see WithInterpolation).
+func interpolate(v reflect.Value, opts *Options) (err error) {
+	ip := &interpolator{
+		optsInterpolationLoader: *opts.interpolation, opts: opts,
+		ssm: map[string]string{}, missing: map[string]bool{}, cache: map[string]resolvedRef{},
+	}
+	dec := decoder{}.withCustom(opts)
+	names := map[string]bool{}
+
+	// Fetch the SSM parameters referenced directly by the config all at once, upfront.
+	walkStrings(v, "", dec, func(s, _ string) (string, error) { //nolint:errcheck // fn never fails.
+		for _, name := range ssmRefNames(s) {
+			names[name] = true
+		}
+
+		return s, nil
+	})
+
+	if len(names) > 0 {
+		if err = ip.fetch(slices.Sorted(maps.Keys(names))); err != nil {
+			return
+		}
+	}
+
+	err = walkStrings(v, "", dec, func(s, path string) (out string, err error) {
+		if !strings.Contains(s, "${") {
+			return s, nil
+		}
+
+		ip.path, ip.ssmUsed = path, ""
+
+		if out, err = ip.expand(s, nil); err == nil && ip.ssmUsed != "" && path != "" {
+			opts.MarkSet(path, "ssm:"+ip.ssmUsed)
+		}
+
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	if len(ip.problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnresolvedRefs, strings.Join(ip.problems, "; "))
+	}
+
+	return
+}
+
+// walkStrings replaces each string value of v (descending into pointers, structs, slices, arrays,
+// maps and interfaces) with fn(value, path), where path is the path of the field holding it.
+func walkStrings(v reflect.Value, path string, dec decoder, fn func(s, path string) (string, error)) (err error) {
+	if v.CanAddr() {
+		if e, ok := v.Addr().Interface().(stringExpander); ok {
+			return e.expandString(func(s string) (string, error) { return fn(s, path) })
+		}
+	}
+
+	switch v.Kind() { //nolint:exhaustive // ok
+	case reflect.String:
+		var s string
+
+		if s, err = fn(v.String(), path); err == nil && s != v.String() {
+			v.SetString(s)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return walkStrings(v.Elem(), path, dec, fn)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			break
+		}
+
+		e := reflect.New(v.Elem().Type()).Elem()
+		e.Set(v.Elem())
+
+		if err = walkStrings(e, path, dec, fn); err == nil {
+			v.Set(e)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err = walkStrings(v.Index(i), path, dec, fn); err != nil {
+				return
+			}
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(k))
+
+			if err = walkStrings(e, path, dec, fn); err != nil {
+				return
+			}
+
+			v.SetMapIndex(k, e)
+		}
+	case reflect.Struct:
+		if dec.decodable(v.Type()) {
+			break
+		}
+
+		for i := range v.NumField() {
+			if fv := v.Field(i); fv.CanSet() {
+				if err = walkStrings(fv, joinPath(path, v.Type().Field(i).Name), dec, fn); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// ssmRefNames returns the names of the SSM parameters referenced (directly) by s.
+func ssmRefNames(s string) (names []string) {
+	for i := strings.Index(s, "${ssm:"); i >= 0; i = strings.Index(s, "${ssm:") {
+		escaped := i > 0 && s[i-1] == '$'
+		s = s[i+len("${ssm:"):]
+
+		if end := strings.IndexByte(s, '}'); end >= 0 && !escaped {
+			name, _, _ := strings.Cut(s[:end], ":-")
+			names = append(names, name)
+		}
+	}
+
+	return
+}
+
+// refEnd returns the index of the closing brace of the reference s starts with, or -1.
+func refEnd(s string) int {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// expand replaces the references in s with their (expanded) values. The stack holds the
+// references being resolved, which s is (part of) the value of, to detect cycles.
+func (ip *interpolator) expand(s string, stack []string) (_ string, err error) {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "$${") {
+			b.WriteString("${")
+			i += 2
+
+			continue
+		}
+
+		end := -1
+		if strings.HasPrefix(s[i:], "${") {
+			end = refEnd(s[i:])
+		}
+
+		if end < 0 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		ref, def, hasDef := strings.Cut(s[i+2:i+end], ":-")
+
+		val, ok, err := ip.resolve(ref, stack)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case hasDef && val == "":
+			if val, err = ip.expand(def, stack); err != nil {
+				return "", err
+			}
+		case !ok:
+			ip.problem("${" + ref + "}")
+		}
+
+		b.WriteString(val)
+
+		i += end
+	}
+
+	return b.String(), nil
+}
+
+// resolve returns the expanded value of the reference ref (an env var name or "ssm:" followed
+// by an SSM parameter name) and whether it is set. Cycles are reported as problems.
+func (ip *interpolator) resolve(ref string, stack []string) (val string, ok bool, err error) {
+	if i := slices.Index(stack, ref); i >= 0 {
+		ip.problem("reference cycle " + strings.Join(append(slices.Clone(stack[i:]), ref), " -> "))
+		return "", true, nil
+	}
+
+	if r, cached := ip.cache[ref]; cached {
+		ip.ssmUsed = cmp.Or(ip.ssmUsed, r.ssm)
+		return r.val, true, nil
+	}
+
+	name, isSSM := strings.CutPrefix(ref, "ssm:")
+	if isSSM {
+		val, ok, err = ip.getSSM(name)
+	} else {
+		val, ok = os.LookupEnv(ref)
+	}
+
+	if err != nil || !ok {
+		return
+	}
+
+	prevSSM, problems := ip.ssmUsed, len(ip.problems)
+	ip.ssmUsed = ""
+
+	if val, err = ip.expand(val, append(stack, ref)); err != nil {
+		return
+	}
+
+	r := resolvedRef{val: val, ssm: ip.ssmUsed}
+	if isSSM {
+		r.ssm = name
+	}
+
+	if len(ip.problems) == problems {
+		ip.cache[ref] = r
+	}
+
+	ip.ssmUsed = cmp.Or(prevSSM, r.ssm)
+
+	return val, true, nil
+}
+
+// problem records a problem with the value of the current field, once.
+func (ip *interpolator) problem(msg string) {
+	if ip.path != "" {
+		msg = ip.path + ": " + msg
+	}
+
+	if !slices.Contains(ip.problems, msg) {
+		ip.problems = append(ip.problems, msg)
+	}
+}
+
+// getSSM returns the value of the SSM parameter name, fetching it if needed, and whether it exists.
+func (ip *interpolator) getSSM(name string) (val string, ok bool, err error) {
+	if val, ok = ip.ssm[name]; ok || ip.missing[name] {
+		return
+	}
+
+	if err = ip.fetch([]string{name}); err != nil {
+		return
+	}
+
+	val, ok = ip.ssm[name]
+
+	return
+}
+
+// fetch fetches the given SSM parameters, creating the SSM client on first use.
+func (ip *interpolator) fetch(names []string) (err error) {
+	ctx := ip.opts.context()
+
+	if ip.svc == nil {
+		if ip.svc, err = ssmClient(ctx, ip.opts, ip.awsRegion, ip.profile); err != nil {
+			return
+		}
+	}
+
+	values, missing, err := fetchSSMParams(ctx, ip.svc, names)
+	if err != nil {
+		return
+	}
+
+	maps.Copy(ip.ssm, values)
+
+	for _, name := range missing {
+		ip.missing[name] = true
+	}
+
+	return
+}
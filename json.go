@@ -1,17 +1,26 @@
 package confetti
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/alexaandru/confetti/internal/remap"
 )
 
 // jsonLoader loads config from a JSON file, []byte, or io.Reader.
+// Files are opened (and io.ReadSeekers rewound) on every Load.
 type jsonLoader struct {
-	r   io.ReadSeeker
-	c   io.Closer
-	err error
+	r    io.ReadSeeker
+	path string
+	err  error
+	src  string
 }
 
 var (
@@ -19,40 +28,87 @@ var (
 	ErrNoDataSource  = errors.New("no data source for JSON loader")
 )
 
-func (j jsonLoader) Load(config any, ownConfig *confetti) (err error) {
+func (j jsonLoader) Load(config any, opts *Options) (err error) {
 	if j.err != nil {
 		return j.err
 	}
 
-	if j.c != nil {
-		defer j.c.Close() //nolint:errcheck // ok
+	if j.path != "" {
+		f, err := os.Open(j.path) //nolint:gosec // this is the whole point of the library.
+		if err != nil {
+			return err
+		}
+
+		defer f.Close() //nolint:errcheck // ok
+
+		return loadJSON(f, config, opts)
 	}
 
 	if j.r == nil {
 		return ErrNoDataSource
 	}
 
-	var errOnUnknown bool
-
-	if ownConfig != nil {
-		errOnUnknown = ownConfig.errOnUnknown
+	if _, err = j.r.Seek(0, io.SeekStart); err != nil {
+		return
 	}
 
-	return loadJSON(j.r, config, errOnUnknown)
+	return loadJSON(j.r, config, opts)
 }
 
-func loadJSON(r io.ReadSeeker, config any, errOnUnknown bool) (err error) {
-	// First pass: decode and populate all known fields, ignore unknowns.
+// File returns the path of the JSON file, if loading from one (see FileLoader).
+func (j jsonLoader) File() string {
+	return j.path
+}
+
+func (j jsonLoader) String() string {
+	return "json:" + j.src
+}
+
+func loadJSON(r io.ReadSeeker, config any, opts *Options) (err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+
+	r = bytes.NewReader(durationsToNanos(data, config))
+
+	// First pass: decode (or merge, see WithMerge) and populate all known fields, ignore unknowns.
 	dec := json.NewDecoder(r)
-	if err = dec.Decode(config); err != nil {
+
+	if opts != nil && opts.merge {
+		var raw json.RawMessage
+
+		if err = dec.Decode(&raw); err != nil {
+			return
+		}
+
+		if err = (merger{tags: true}).mergeJSON(reflect.ValueOf(config).Elem(), raw, "", ""); err != nil {
+			return
+		}
+	} else if err = dec.Decode(config); err != nil {
 		return
 	}
 
-	if !errOnUnknown {
+	// Second pass: rewind and record which fields were present in the source.
+	if opts != nil {
+		if _, err = r.Seek(0, io.SeekStart); err != nil {
+			return
+		}
+
+		var raw json.RawMessage
+
+		if err = json.NewDecoder(r).Decode(&raw); err != nil {
+			return
+		}
+
+		markJSON(raw, reflect.TypeOf(config), "", opts)
+	}
+
+	if opts == nil || !opts.ErrOnUnknown {
 		return
 	}
 
-	// Second pass: rewind and check for unknown fields.
+	// Third pass: rewind and check for unknown fields, decoding into a throwaway config.
 	if _, err = r.Seek(0, io.SeekStart); err != nil {
 		return
 	}
@@ -61,9 +117,188 @@ func loadJSON(r io.ReadSeeker, config any, errOnUnknown bool) (err error) {
 
 	dec.DisallowUnknownFields()
 
-	if err = dec.Decode(config); err != nil {
+	if err = dec.Decode(reflect.New(reflect.TypeOf(config).Elem()).Interface()); err != nil {
 		return fmt.Errorf("%w: %w", ErrUnknownFields, err)
 	}
 
 	return
 }
+
+// durationsToNanos converts the strings (e.g. "5s") meant for the time.Duration fields of config
+// in the JSON document data to nanoseconds, as encoding/json only decodes durations from those.
+// Invalid documents are returned as they are, for the decoder to report.
+func durationsToNanos(data []byte, config any) []byte {
+	doc, err := decodeAny(data)
+	if err != nil {
+		return data
+	}
+
+	if conv, err := remap.ToJSON(doc, config, "json"); err == nil {
+		return conv
+	}
+
+	return data
+}
+
+// markJSON marks (see Options.MarkSet) every field of type t present in the JSON document data.
+// Nested structs are descended into, anything else is marked as a whole.
+func markJSON(data json.RawMessage, t reflect.Type, path string, opts *Options) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var obj map[string]json.RawMessage
+
+	if t.Kind() != reflect.Struct || json.Unmarshal(data, &obj) != nil {
+		opts.MarkSet(path, "")
+		return
+	}
+
+	for key, raw := range obj {
+		if name, ft, ok := jsonField(t, key); ok {
+			markJSON(raw, ft, joinPath(path, name), opts)
+		}
+	}
+}
+
+// jsonField finds the field of struct type t that encoding/json would decode key into,
+// preferring an exact match over a case-insensitive one. It returns the (dot separated)
+// path of the field relative to t, descending into embedded structs, and its type.
+func jsonField(t reflect.Type, key string) (path string, ft reflect.Type, ok bool) {
+	for _, f := range jsonFields(t) {
+		if f.name == key {
+			return f.path, f.typ, true
+		}
+
+		if !ok && strings.EqualFold(f.name, key) {
+			path, ft, ok = f.path, f.typ, true
+		}
+	}
+
+	return
+}
+
+// jsonFieldInfo describes a field of a struct, as seen by encoding/json.
+type jsonFieldInfo struct {
+	name, path string
+	typ        reflect.Type
+	tagged     bool
+}
+
+// jsonFields returns the fields of struct type t that encoding/json decodes into, including
+// those promoted from embedded structs (and pointers to structs), breadth first. Like with
+// encoding/json, a field hides the ones of the same name nested deeper and, at the same depth,
+// a tagged field hides untagged ones, while several fields still left with the same name hide
+// each other.
+func jsonFields(t reflect.Type) (fields []jsonFieldInfo) {
+	type embedded struct {
+		t    reflect.Type
+		path string
+	}
+
+	current, visited, hidden := []embedded{{t: t}}, map[reflect.Type]bool{}, map[string]bool{}
+
+	for len(current) > 0 {
+		var (
+			next   []embedded
+			level  []jsonFieldInfo
+			byName = map[string][]int{}
+		)
+
+		for _, e := range current {
+			if visited[e.t] {
+				continue
+			}
+
+			visited[e.t] = true
+
+			for i := range e.t.NumField() {
+				field := e.t.Field(i)
+
+				name, visible := jsonName(field)
+				if !visible {
+					continue
+				}
+
+				path := joinPath(e.path, field.Name)
+
+				if name == "" {
+					et := field.Type
+					if et.Kind() == reflect.Ptr {
+						et = et.Elem()
+					}
+
+					next = append(next, embedded{t: et, path: path})
+
+					continue
+				}
+
+				tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				byName[name] = append(byName[name], len(level))
+				level = append(level, jsonFieldInfo{name: name, path: path, typ: field.Type, tagged: tag != ""})
+			}
+		}
+
+		for i, f := range level {
+			if hidden[f.name] || !dominant(level, byName[f.name], i) {
+				continue
+			}
+
+			fields = append(fields, f)
+		}
+
+		for name := range byName {
+			hidden[name] = true
+		}
+
+		current = next
+	}
+
+	return
+}
+
+// dominant reports whether the field i of level wins over the others having the same name
+// (given by their indexes), at the same depth: either it is the only one or the only tagged one.
+func dominant(level []jsonFieldInfo, same []int, i int) bool {
+	if len(same) == 1 {
+		return true
+	}
+
+	tagged := 0
+
+	for _, j := range same {
+		if level[j].tagged {
+			tagged++
+		}
+	}
+
+	return tagged == 1 && level[i].tagged
+}
+
+// jsonName returns the JSON key of field (its `json` tag name or its Go name) and whether
+// encoding/json considers the field at all. Embedded untagged structs (and pointers to structs)
+// have an empty name.
+func jsonName(field reflect.StructField) (name string, visible bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ = strings.Cut(tag, ",")
+
+	if field.Anonymous && name == "" {
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			return "", true
+		case field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct:
+			// Like encoding/json, pointers to unexported structs are ignored, as they cannot be allocated.
+			return "", field.IsExported()
+		}
+	}
+
+	if !field.IsExported() {
+		return "", false
+	}
+
+	return cmp.Or(name, field.Name), true
+}
@@ -0,0 +1,198 @@
+package confetti
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type optsMergeLoader struct{}
+
+func (optsMergeLoader) Load(_ any, opts *Options) (err error) {
+	opts.merge = true
+	return
+}
+
+// WithMerge makes the JSON based loaders (e.g. WithJSON, WithSSM) merge their documents into
+// the config, rather than decoding them like encoding/json would, so that layered sources
+// (e.g. a base config and per environment overlays) combine predictably:
+//   - an explicit null resets a field to its zero value (and deletes a map entry);
+//   - structs and maps are merged deeply, key by key;
+//   - slices are replaced.
+//
+// The `merge` struct tag overrides the strategy of a field: "replace" replaces structs, maps
+// and slices wholesale, "append" appends to slices (and adds map entries without merging their
+// values) and "deep" merges slices element by element (by index).
+func WithMerge() optsMergeLoader {
+	return optsMergeLoader{}
+}
+
+// merger merges JSON documents into config values (see WithMerge).
+type merger struct {
+	// tags is true if the strategies set by the `merge` tags apply, false to use the default ones.
+	tags bool
+}
+
+// mergeJSON merges the JSON document data into v, using the given strategy ("replace", "append",
+// "deep" or "" for the default one, see WithMerge). The path is used as the prefix of errors.
+func (m merger) mergeJSON(v reflect.Value, data json.RawMessage, strategy, path string) (err error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		v.SetZero()
+		return
+	}
+
+	t := v.Type()
+
+	switch {
+	case (decoder{}).decodable(t) && t.Kind() != reflect.Ptr:
+		return decodeJSON(v, data, path)
+	case t.Kind() == reflect.Ptr:
+		if v.IsNil() || strategy == "replace" {
+			v.Set(reflect.New(t.Elem()))
+		}
+
+		return m.mergeJSON(v.Elem(), data, strategy, path)
+	case t.Kind() == reflect.Struct:
+		return m.mergeStruct(v, data, strategy, path)
+	case t.Kind() == reflect.Map:
+		return m.mergeMap(v, data, strategy, path)
+	case t.Kind() == reflect.Slice && strategy == "append":
+		fresh := reflect.New(t).Elem()
+		if err = decodeJSON(fresh, data, path); err != nil {
+			return
+		}
+
+		v.Set(reflect.AppendSlice(v, fresh))
+	case t.Kind() == reflect.Slice && strategy == "deep":
+		var items []json.RawMessage
+
+		if err = json.Unmarshal(data, &items); err != nil {
+			return decodeJSON(v, data, path)
+		}
+
+		if len(items) > v.Len() {
+			grown := reflect.MakeSlice(t, len(items), len(items))
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+
+		for i, item := range items {
+			if err = m.mergeJSON(v.Index(i), item, "", fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return
+			}
+		}
+	default:
+		return decodeJSON(v, data, path)
+	}
+
+	return
+}
+
+func (m merger) mergeStruct(v reflect.Value, data json.RawMessage, strategy, path string) (err error) {
+	var obj map[string]json.RawMessage
+
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return decodeJSON(v, data, path)
+	}
+
+	if strategy == "replace" {
+		v.SetZero()
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		fieldPath, _, ok := jsonField(v.Type(), key)
+		if !ok {
+			continue // Unknown fields are reported (or not) by loadJSON.
+		}
+
+		fv, field := fieldByPath(v, fieldPath)
+
+		fieldStrategy := ""
+		if m.tags {
+			fieldStrategy = field.Tag.Get("merge")
+		}
+
+		if err = m.mergeJSON(fv, obj[key], fieldStrategy, joinPath(path, fieldPath)); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (m merger) mergeMap(v reflect.Value, data json.RawMessage, strategy, path string) (err error) {
+	var obj map[string]json.RawMessage
+
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return decodeJSON(v, data, path)
+	}
+
+	t := v.Type()
+	if v.IsNil() || strategy == "replace" {
+		v.Set(reflect.MakeMapWithSize(t, len(obj)))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		name := fmt.Sprintf("%s[%s]", path, key)
+
+		k := reflect.New(t.Key()).Elem()
+		if err = (decoder{}).setElem(k, key, name); err != nil {
+			return
+		}
+
+		if bytes.Equal(bytes.TrimSpace(obj[key]), []byte("null")) {
+			v.SetMapIndex(k, reflect.Value{})
+			continue
+		}
+
+		e := reflect.New(t.Elem()).Elem()
+		if old := v.MapIndex(k); old.IsValid() && strategy != "append" {
+			e.Set(old)
+		}
+
+		if err = m.mergeJSON(e, obj[key], "", name); err != nil {
+			return
+		}
+
+		v.SetMapIndex(k, e)
+	}
+
+	return
+}
+
+// decodeJSON sets v to the JSON document data, decoded by encoding/json into a fresh value.
+func decodeJSON(v reflect.Value, data json.RawMessage, path string) error {
+	fresh := reflect.New(v.Type())
+	if err := json.Unmarshal(data, fresh.Interface()); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	v.Set(fresh.Elem())
+
+	return nil
+}
+
+// fieldByPath returns the field of struct v at the dot separated path (as returned by
+// jsonField), allocating the nil pointers to embedded structs on the way.
+func fieldByPath(v reflect.Value, path string) (fv reflect.Value, field reflect.StructField) {
+	fv = v
+
+	for name := range strings.SplitSeq(path, ".") {
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+
+			fv = fv.Elem()
+		}
+
+		field, _ = fv.Type().FieldByName(name)
+		fv = fv.FieldByIndex(field.Index)
+	}
+
+	return
+}
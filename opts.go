@@ -1,5 +1,12 @@
 package confetti
 
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"strings"
+)
+
 type optsLoader struct {
 	errOnUnknown bool
 }
@@ -8,12 +15,78 @@ type optsMockedSSMLoader struct {
 	client SSMAPI
 }
 
-func (o optsLoader) Load(_ any, ownConfig *confetti) (err error) {
-	ownConfig.errOnUnknown = o.errOnUnknown
+type optsMockedSecretsManagerLoader struct {
+	client SecretsManagerAPI
+}
+
+type optsDecoderLoader struct {
+	t  reflect.Type
+	fn DecodeFunc
+}
+
+func (o optsLoader) Load(_ any, opts *Options) (err error) {
+	opts.ErrOnUnknown = o.errOnUnknown
+	return
+}
+
+func (o optsMockedSSMLoader) Load(_ any, opts *Options) (err error) {
+	opts.mockedSSM = o.client
+	return
+}
+
+func (o optsMockedSecretsManagerLoader) Load(_ any, opts *Options) (err error) {
+	opts.mockedSecretsManager = o.client
 	return
 }
 
-func (o optsMockedSSMLoader) Load(_ any, ownConfig *confetti) (err error) {
-	ownConfig.mockedSSM = o.client
+func (o optsDecoderLoader) Load(_ any, opts *Options) (err error) {
+	if opts.decoders == nil {
+		opts.decoders = map[reflect.Type]DecodeFunc{}
+	}
+
+	opts.decoders[o.t] = o.fn
+
 	return
 }
+
+// context returns the context of the current Load call, defaulting to context.Background().
+func (o *Options) context() context.Context {
+	if o == nil || o.Context == nil {
+		return context.Background()
+	}
+
+	return o.Context
+}
+
+// MarkSet records that the current loader has set the field at path (the dot separated
+// Go field names, e.g. "Nested.Deep.Foo"). The source optionally describes where the value
+// came from more precisely (e.g. "env:MYAPP_PORT") and defaults to Source.
+//
+// Loaders should call it for every field they populate, otherwise zero values they set
+// on required fields would be reported as missing (and their Provenance would be incomplete).
+func (o *Options) MarkSet(path, source string) {
+	if o == nil {
+		return
+	}
+
+	if o.touched == nil {
+		o.touched = map[string]string{}
+	}
+
+	o.touched[path] = cmp.Or(source, o.Source)
+}
+
+// isSet reports whether any loader has set the field at path (or any of its subfields).
+func (o *Options) isSet(path string) bool {
+	if _, ok := o.touched[path]; ok {
+		return true
+	}
+
+	for p := range o.touched {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
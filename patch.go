@@ -0,0 +1,550 @@
+package confetti
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alexaandru/confetti/internal/remap"
+)
+
+// patchLoader applies a JSON Patch (RFC 6902) or a JSON Merge Patch (RFC 7386) document to the config.
+type patchLoader struct {
+	src   remap.Source
+	merge bool
+}
+
+// patchOp is a JSON Patch operation.
+type patchOp struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// ErrPatchFailed is returned (wrapped) when a JSON Patch operation fails, e.g. a "test" one.
+var ErrPatchFailed = errors.New("patch failed")
+
+func (p patchLoader) Load(config any, opts *Options) (err error) {
+	data, err := p.src.Read()
+	if err != nil {
+		return
+	}
+
+	before, err := json.Marshal(config)
+	if err != nil {
+		return
+	}
+
+	doc, err := decodeAny(before)
+	if err != nil {
+		return
+	}
+
+	if doc, err = completeJSON(doc, reflect.ValueOf(config)); err != nil {
+		return
+	}
+
+	var patched any
+
+	if p.merge {
+		var patch any
+
+		if patch, err = decodeAny(data); err != nil {
+			return
+		}
+
+		patched = mergePatch(deepCopy(doc), patch)
+	} else {
+		var ops []patchOp
+
+		if err = json.Unmarshal(data, &ops); err != nil {
+			return
+		}
+
+		if patched, err = jsonPatch(deepCopy(doc), ops); err != nil {
+			return fmt.Errorf("%w: %w", ErrPatchFailed, err)
+		}
+	}
+
+	diff, changed := mergeDiff(doc, patched)
+	if !changed {
+		return
+	}
+
+	return applyDiff(config, diff, opts)
+}
+
+func (p patchLoader) String() string {
+	if p.merge {
+		return "mergepatch:" + p.src.Name
+	}
+
+	return "jsonpatch:" + p.src.Name
+}
+
+// File returns the path of the patch file, if loading from one (see FileLoader).
+func (p patchLoader) File() string {
+	return p.src.Path()
+}
+
+// applyDiff applies the merge patch diff, as computed by mergeDiff, to config: only the fields
+// the patch changed are touched, so values which do not survive a round trip through JSON
+// are preserved.
+func applyDiff(config, diff any, opts *Options) (err error) {
+	data, err := remap.ToJSON(diff, config, "json")
+	if err != nil {
+		return
+	}
+
+	if err = (merger{}).mergeJSON(reflect.ValueOf(config).Elem(), data, "", ""); err != nil {
+		return
+	}
+
+	markJSON(data, reflect.TypeOf(config), "", opts)
+
+	if opts == nil || !opts.ErrOnUnknown {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err = dec.Decode(reflect.New(reflect.TypeOf(config).Elem()).Interface()); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnknownFields, err)
+	}
+
+	return
+}
+
+// completeJSON completes doc, the JSON document v was marshaled to, so that the patch sees (and
+// preserves) all of v: the fields omitted as empty (see the omitempty option of encoding/json)
+// are added and the redacted secrets (see Secret) are replaced with their actual values.
+func completeJSON(doc any, v reflect.Value) (_ any, err error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return doc, nil
+		}
+
+		v = v.Elem()
+	}
+
+	if !v.CanInterface() {
+		return doc, nil
+	}
+
+	if sc, ok := v.Interface().(interface{ plainJSON() ([]byte, error) }); ok {
+		data, err := sc.plainJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeAny(data)
+	}
+
+	if _, ok := v.Interface().(json.Marshaler); ok {
+		return doc, nil
+	}
+
+	switch d := doc.(type) {
+	case map[string]any:
+		switch v.Kind() { //nolint:exhaustive // ok
+		case reflect.Struct:
+			for _, f := range jsonFields(v.Type()) {
+				fv := fieldAtPath(v, f.path)
+				if !fv.IsValid() || !fv.CanInterface() {
+					continue
+				}
+
+				if _, ok := d[f.name]; !ok {
+					// Dropped by omitempty.
+					var data []byte
+
+					if data, err = json.Marshal(fv.Interface()); err != nil {
+						return
+					}
+
+					if d[f.name], err = decodeAny(data); err != nil {
+						return
+					}
+				}
+
+				if d[f.name], err = completeJSON(d[f.name], fv); err != nil {
+					return
+				}
+			}
+		case reflect.Map:
+			if v.Type().Key().Implements(reflect.TypeFor[encoding.TextMarshaler]()) {
+				break
+			}
+
+			for iter := v.MapRange(); iter.Next(); {
+				k := jsonMapKey(iter.Key())
+				if _, ok := d[k]; ok {
+					if d[k], err = completeJSON(d[k], iter.Value()); err != nil {
+						return
+					}
+				}
+			}
+		}
+	case []any:
+		if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() != len(d) {
+			break
+		}
+
+		for i := range d {
+			if d[i], err = completeJSON(d[i], v.Index(i)); err != nil {
+				return
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// jsonMapKey returns the JSON key of the map key k, which is a string or an integer.
+func jsonMapKey(k reflect.Value) string {
+	switch k.Kind() { //nolint:exhaustive // ok
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	default:
+		return k.String()
+	}
+}
+
+// fieldAtPath returns the field of struct v at the dot separated path (as returned by jsonFields)
+// or the zero Value if a pointer to an embedded struct on the way is nil.
+func fieldAtPath(v reflect.Value, path string) reflect.Value {
+	for name := range strings.SplitSeq(path, ".") {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.FieldByName(name)
+	}
+
+	return v
+}
+
+// decodeAny decodes the JSON document data, keeping numbers as json.Number.
+func decodeAny(data []byte) (v any, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	err = dec.Decode(&v)
+
+	return
+}
+
+// mergePatch applies the JSON Merge Patch (RFC 7386) patch to doc.
+func mergePatch(doc, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	d, ok := doc.(map[string]any)
+	if !ok {
+		d = map[string]any{}
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(d, k)
+			continue
+		}
+
+		d[k] = mergePatch(d[k], v)
+	}
+
+	return d
+}
+
+// mergeDiff returns the JSON Merge Patch turning a into b and whether they differ at all.
+func mergeDiff(a, b any) (diff any, changed bool) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+
+	if !aok || !bok {
+		return b, !reflect.DeepEqual(a, b)
+	}
+
+	d := map[string]any{}
+
+	for k, av := range am {
+		bv, ok := bm[k]
+		if !ok {
+			d[k] = nil
+			continue
+		}
+
+		if sub, ch := mergeDiff(av, bv); ch {
+			d[k] = sub
+		}
+	}
+
+	for k, bv := range bm {
+		if _, ok := am[k]; !ok {
+			d[k] = bv
+		}
+	}
+
+	return d, len(d) > 0
+}
+
+// jsonPatch applies the JSON Patch (RFC 6902) operations to doc.
+func jsonPatch(doc any, ops []patchOp) (_ any, err error) {
+	for i, op := range ops {
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func (op patchOp) apply(doc any) (_ any, err error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return
+	}
+
+	var val any
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, errors.New("missing value")
+		}
+
+		if val, err = decodeAny(*op.Value); err != nil {
+			return
+		}
+	case "move", "copy":
+		from, fromErr := parsePointer(op.From)
+		if fromErr != nil {
+			return nil, fromErr
+		}
+
+		if op.Op == "move" && len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+			return nil, errors.New("cannot move a value into itself")
+		}
+
+		if val, err = pointerGet(doc, from); err != nil {
+			return
+		}
+
+		if op.Op == "copy" {
+			val = deepCopy(val)
+		} else if doc, _, err = pointerRemove(doc, from); err != nil {
+			return
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+
+	switch op.Op {
+	case "remove":
+		doc, _, err = pointerRemove(doc, path)
+	case "replace":
+		doc, err = pointerSet(doc, path, val, true)
+	case "test":
+		var cur any
+
+		if cur, err = pointerGet(doc, path); err == nil && !reflect.DeepEqual(cur, val) {
+			err = errors.New("test failed")
+		}
+	default:
+		doc, err = pointerSet(doc, path, val, false)
+	}
+
+	return doc, err
+}
+
+// parsePointer parses the JSON Pointer (RFC 6901) s into its (unescaped) reference tokens.
+func parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q", s)
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses the array index token for an array of length n. The end index (n, or "-")
+// is only valid if end is true.
+func arrayIndex(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > n || (i == n && !end) || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	return i, nil
+}
+
+func pointerGet(doc any, path []string) (any, error) {
+	for _, t := range path {
+		switch n := doc.(type) {
+		case map[string]any:
+			v, ok := n[t]
+			if !ok {
+				return nil, fmt.Errorf("path not found: %q", t)
+			}
+
+			doc = v
+		case []any:
+			i, err := arrayIndex(t, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+
+			doc = n[i]
+		default:
+			return nil, fmt.Errorf("path not found: %q", t)
+		}
+	}
+
+	return doc, nil
+}
+
+// pointerSet adds val at path in doc (or replaces the existing value, which must exist, if
+// replace is true) and returns the resulting document.
+func pointerSet(doc any, path []string, val any, replace bool) (any, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+
+	t, last := path[0], len(path) == 1
+
+	switch n := doc.(type) {
+	case map[string]any:
+		child, ok := n[t]
+		if !ok && (!last || replace) {
+			return nil, fmt.Errorf("path not found: %q", t)
+		}
+
+		if last {
+			n[t] = val
+			return n, nil
+		}
+
+		child, err := pointerSet(child, path[1:], val, replace)
+		if err != nil {
+			return nil, err
+		}
+
+		n[t] = child
+
+		return n, nil
+	case []any:
+		i, err := arrayIndex(t, len(n), last && !replace)
+		if err != nil {
+			return nil, err
+		}
+
+		if !last || replace {
+			if n[i], err = pointerSet(n[i], path[1:], val, replace); err != nil {
+				return nil, err
+			}
+
+			return n, nil
+		}
+
+		return slices.Insert(n, i, val), nil
+	default:
+		return nil, fmt.Errorf("path not found: %q", t)
+	}
+}
+
+// pointerRemove removes the value at path from doc and returns the resulting document and the value.
+func pointerRemove(doc any, path []string) (_, removed any, err error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	t, last := path[0], len(path) == 1
+
+	switch n := doc.(type) {
+	case map[string]any:
+		child, ok := n[t]
+		if !ok {
+			return nil, nil, fmt.Errorf("path not found: %q", t)
+		}
+
+		if last {
+			delete(n, t)
+			return n, child, nil
+		}
+
+		if n[t], removed, err = pointerRemove(child, path[1:]); err != nil {
+			return
+		}
+
+		return n, removed, nil
+	case []any:
+		i, err := arrayIndex(t, len(n), false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if last {
+			removed = n[i]
+			return slices.Delete(n, i, i+1), removed, nil
+		}
+
+		if n[i], removed, err = pointerRemove(n[i], path[1:]); err != nil {
+			return nil, nil, err
+		}
+
+		return n, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("path not found: %q", t)
+	}
+}
+
+// deepCopy returns a copy of the decoded JSON value v, sharing nothing with it.
+func deepCopy(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for k, e := range x {
+			m[k] = deepCopy(e)
+		}
+
+		return m
+	case []any:
+		s := make([]any, len(x))
+		for i, e := range x {
+			s[i] = deepCopy(e)
+		}
+
+		return s
+	default:
+		return v
+	}
+}
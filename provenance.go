@@ -0,0 +1,68 @@
+package confetti
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Provenance maps the path of each config field set during a Load call (the dot separated
+// Go field names, e.g. "Nested.Deep.Foo") to the source which last set it, such as "default",
+// "env:MYAPP_PORT", "json:config.json" or "ssm:/prod/app". Fields no loader set are absent.
+//
+// It is filled in by Load when passed WithProvenance and is meant to be logged at startup,
+// either as text (see String) or via log/slog (see LogValue).
+type Provenance map[string]string
+
+type optsProvenanceLoader struct {
+	p *Provenance
+}
+
+func (o optsProvenanceLoader) Load(_ any, opts *Options) (err error) {
+	opts.provenance = o.p
+	return
+}
+
+// WithProvenance makes Load record where each field got its value from into p (see Provenance).
+// It is filled in even if Load fails, with the fields set up to that point.
+func WithProvenance(p *Provenance) optsProvenanceLoader {
+	return optsProvenanceLoader{p: p}
+}
+
+// Source returns the source of the field at path, or that of its closest parent which was set
+// as a whole (e.g. a map loaded from JSON), or "" if no loader set it.
+func (p Provenance) Source(path string) string {
+	for {
+		if src, ok := p[path]; ok {
+			return src
+		}
+
+		i := strings.LastIndexByte(path, '.')
+		if i < 0 {
+			return ""
+		}
+
+		path = path[:i]
+	}
+}
+
+// String returns the provenance of all the fields, one "path: source" per line, sorted by path.
+func (p Provenance) String() string {
+	lines := []string{}
+	for _, path := range slices.Sorted(maps.Keys(p)) {
+		lines = append(lines, path+": "+p[path])
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// LogValue implements slog.LogValuer, logging the provenance as a group of path=source attributes.
+func (p Provenance) LogValue() slog.Value {
+	attrs := []slog.Attr{}
+	for _, path := range slices.Sorted(maps.Keys(p)) {
+		attrs = append(attrs, slog.String(path, p[path]))
+	}
+
+	return slog.GroupValue(attrs...)
+}
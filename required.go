@@ -0,0 +1,89 @@
+package confetti
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrMissingRequired = errors.New("missing required fields")
+
+// checkRequired returns an ErrMissingRequired error listing every required field of v
+// which is still zero and was never set by any loader, or nil if there are none.
+func checkRequired(v reflect.Value, opts *Options) error {
+	prefixes := []string{}
+
+	for _, p := range opts.envPrefixes {
+		prefixes = append(prefixes, strings.ToUpper(p))
+	}
+
+	missing := missingRequired(v, "", "", prefixes, decoder{}.withCustom(opts), opts)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, "; "))
+}
+
+// missingRequired recursively collects the required fields of struct v that are missing,
+// each described by its field path, the env var names (one for each of the env loaders
+// used, as given by prefixes) and the JSON path it could have been loaded from.
+func missingRequired(v reflect.Value, path, jsonPath string, prefixes []string, dec decoder, opts *Options) (missing []string) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		fieldVal := v.Field(i)
+
+		if !fieldVal.CanSet() {
+			continue
+		}
+
+		fieldPath := joinPath(path, field.Name)
+		subPrefixes := make([]string, len(prefixes))
+
+		for j, p := range prefixes {
+			subPrefixes[j] = envVarName(field, p)
+		}
+
+		subJSONPath := jsonPath
+
+		name, visible := jsonName(field)
+		if name != "" {
+			subJSONPath = joinPath(jsonPath, name)
+		}
+
+		if fieldVal.Kind() == reflect.Struct && dec.nested(fieldVal.Type()) {
+			missing = append(missing, missingRequired(fieldVal, fieldPath, subJSONPath, subPrefixes, dec, opts)...)
+			continue
+		}
+
+		if fieldVal.Kind() == reflect.Ptr && dec.nested(fieldVal.Type()) && !fieldVal.IsNil() {
+			missing = append(missing, missingRequired(fieldVal.Elem(), fieldPath, subJSONPath, subPrefixes, dec, opts)...)
+			continue
+		}
+
+		_, envRequired := envTag(field)
+		if field.Tag.Get("required") != "true" && !envRequired {
+			continue
+		}
+
+		if opts.isSet(fieldPath) || !fieldVal.IsZero() {
+			continue
+		}
+
+		sources := []string{}
+		for _, p := range subPrefixes {
+			sources = append(sources, "env "+p)
+		}
+
+		if visible {
+			sources = append(sources, "json "+subJSONPath)
+		}
+
+		missing = append(missing, fmt.Sprintf("%s (%s)", fieldPath, strings.Join(sources, ", ")))
+	}
+
+	return
+}
@@ -0,0 +1,377 @@
+package confetti
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// jsonSchemaDialect is the JSON Schema version of the schemas returned by JSONSchema.
+	jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+	// durationPattern matches the durations accepted by time.ParseDuration.
+	durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+	// hostPortPattern matches the host:port pairs accepted by the hostport validation rule.
+	hostPortPattern = `:[0-9]{1,5}$`
+)
+
+// schema is a JSON Schema (or a part of one).
+type schema = map[string]any
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing the JSON documents the config cfg
+// (a struct or a pointer to one) can be loaded from (see WithJSON), e.g. for validating config
+// files in editors or CI. Fields are named after their `json` tags and nested structs are
+// described inline. Unknown properties are not allowed. Besides, the schema honours:
+//   - the `desc` struct tag, as the description of the field;
+//   - the `default` struct tag, as its default value;
+//   - the `required` struct tag (and `env:",required"`), as a required property;
+//   - the `validate` struct tag rules (see Validator), except for Validate methods;
+//   - Secret fields, which are write only.
+//
+// Durations are described as strings, in time.ParseDuration format (e.g. "1m30s"), which is
+// checked by a pattern, or as integers (nanoseconds), as the JSON loaders accept both.
+//
+// It returns an error if any of the `default` or `validate` tags is invalid.
+func JSONSchema(cfg any) ([]byte, error) {
+	t := reflect.TypeOf(cfg)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a struct or a pointer to one (got %T)", cfg)
+	}
+
+	s, err := schemaOf(t, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s["$schema"] = jsonSchemaDialect
+	if t.Name() != "" {
+		s["title"] = t.Name()
+	}
+
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err = enc.Encode(s); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// schemaOf returns the schema of the values of type t. The path (of the field holding them)
+// is used as the prefix of errors and stack holds the structs being described, to stop at
+// recursive types, which are left unconstrained.
+func schemaOf(t reflect.Type, path string, stack []reflect.Type) (schema, error) {
+	dec := decoder{}
+
+	switch {
+	case t.Implements(reflect.TypeFor[interface{ isSecret() }]()):
+		m, _ := t.MethodByName("Value")
+
+		s, err := schemaOf(m.Type.Out(0), path, stack)
+		if err == nil {
+			s["writeOnly"] = true
+		}
+
+		return s, err
+	case t == reflect.TypeFor[time.Duration]():
+		return schema{"type": []string{"string", "integer"}, "pattern": durationPattern}, nil
+	case t == reflect.TypeFor[time.Time]():
+		return schema{"type": "string", "format": "date-time"}, nil
+	case t == reflect.TypeFor[url.URL]():
+		return schema{"type": "string", "format": "uri"}, nil
+	case t.Kind() == reflect.Ptr:
+		return schemaOf(t.Elem(), path, stack)
+	case dec.decodable(t):
+		if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+			return schema{"type": "string"}, nil
+		}
+
+		return schema{}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive // ok
+	case reflect.Struct:
+		return structSchema(t, path, stack)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return schema{"type": "string", "contentEncoding": "base64"}, nil
+		}
+
+		items, err := schemaOf(t.Elem(), path, stack)
+		if err != nil {
+			return nil, err
+		}
+
+		s := schema{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			s["minItems"], s["maxItems"] = t.Len(), t.Len()
+		}
+
+		return s, nil
+	case reflect.Map:
+		values, err := schemaOf(t.Elem(), path, stack)
+		if err != nil {
+			return nil, err
+		}
+
+		return schema{"type": "object", "additionalProperties": values}, nil
+	case reflect.Bool:
+		return schema{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return schema{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}, nil
+	case reflect.String:
+		return schema{"type": "string"}, nil
+	default:
+		return schema{}, nil
+	}
+}
+
+// structSchema returns the schema of the struct type t (see schemaOf).
+func structSchema(t reflect.Type, path string, stack []reflect.Type) (schema, error) {
+	if slices.Contains(stack, t) {
+		return schema{}, nil
+	}
+
+	s := schema{"type": "object", "properties": schema{}, "additionalProperties": false}
+	if err := addFields(s, t, path, append(stack, t)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// addFields adds the fields of struct type t, including those of its embedded structs,
+// to the properties (and required properties) of the object schema s.
+func addFields(s schema, t reflect.Type, path string, stack []reflect.Type) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, visible := jsonName(field)
+		if !visible {
+			continue
+		}
+
+		if name == "" {
+			et := field.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+
+			if err := addFields(s, et, path, stack); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fs, err := fieldSchema(field, joinPath(path, field.Name), stack)
+		if err != nil {
+			return err
+		}
+
+		s["properties"].(schema)[name] = fs //nolint:forcetypeassert // always set by structSchema.
+
+		if _, required := envTag(field); required || field.Tag.Get("required") == "true" {
+			req, _ := s["required"].([]string)
+			s["required"] = append(req, name)
+		}
+	}
+
+	return nil
+}
+
+// fieldSchema returns the schema of the struct field at path, as described by its struct tags.
+func fieldSchema(field reflect.StructField, path string, stack []reflect.Type) (schema, error) {
+	s, err := schemaOf(field.Type, path, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	if desc := field.Tag.Get("desc"); desc != "" {
+		s["description"] = desc
+	}
+
+	if def, ok := field.Tag.Lookup("default"); ok && s["writeOnly"] == nil {
+		if s["default"], err = defaultJSON(field.Type, def, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if rules, ok := field.Tag.Lookup("validate"); ok {
+		if err = addRules(s, field.Type, rules, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// defaultJSON returns the JSON encoding of the `default` tag def of a field of type t,
+// converted like loadDefaults does. Durations are kept as they are (e.g. "30s").
+func defaultJSON(t reflect.Type, def, path string) (any, error) {
+	if t == reflect.TypeFor[time.Duration]() {
+		return def, nil
+	}
+
+	v := reflect.New(t).Elem()
+
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}
+	if err := dec.setValue(v, def, "default "+path); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("default %s: %w", path, err)
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// addRules adds the constraints matching the `validate` tag rules (see validateField) of a field
+// of type t to its schema s. With omitempty, they only apply to non zero values.
+func addRules(s schema, t reflect.Type, rules, path string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// The constraints of the value and, for slices, of their items.
+	c, items, omitEmpty := schema{}, schema{}, false
+
+	for rules != "" {
+		var rule string
+
+		if strings.HasPrefix(rules, "regexp=") {
+			rule, rules = rules, ""
+		} else {
+			rule, rules, _ = strings.Cut(rules, ",")
+		}
+
+		rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		target, et := c, t
+		if t.Kind() == reflect.Slice && rule != "min" && rule != "max" {
+			target, et = items, t.Elem()
+		}
+
+		switch rule {
+		case "omitempty":
+			omitEmpty = true
+		case "min", "max":
+			if err := addBound(c, t, rule, arg); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		case "oneof":
+			enum := []any{}
+
+			for _, val := range strings.Fields(arg) {
+				v, err := defaultJSON(et, val, path)
+				if err != nil {
+					return err
+				}
+
+				enum = append(enum, v)
+			}
+
+			target["enum"] = enum
+		case "url":
+			target["format"] = "uri"
+		case "hostport":
+			addPattern(target, hostPortPattern)
+		case "regexp":
+			addPattern(target, arg)
+		default:
+			return fmt.Errorf("%s: unknown validation rule %q", path, rule)
+		}
+	}
+
+	if len(items) > 0 {
+		maps.Copy(s["items"].(schema), items) //nolint:forcetypeassert // set by schemaOf for slices.
+	}
+
+	switch {
+	case len(c) == 0:
+	case !omitEmpty:
+		maps.Copy(s, c)
+	case t.Kind() == reflect.Slice:
+		s["anyOf"] = []schema{{"maxItems": 0}, c}
+	case t.Kind() == reflect.Map:
+		s["anyOf"] = []schema{{"maxProperties": 0}, c}
+	default:
+		s["anyOf"] = []schema{{"const": reflect.Zero(t).Interface()}, c}
+	}
+
+	return nil
+}
+
+// addBound adds the constraint matching the min or max rule with the given bound
+// (see checkBound) for the values of type t to c.
+func addBound(c schema, t reflect.Type, rule, arg string) (err error) {
+	var bound any
+
+	kw := map[string]string{"min": "minimum", "max": "maximum"}[rule]
+
+	switch t.Kind() { //nolint:exhaustive // ok
+	case reflect.String, reflect.Slice, reflect.Map:
+		kw = rule + map[reflect.Kind]string{reflect.String: "Length", reflect.Slice: "Items", reflect.Map: "Properties"}[t.Kind()]
+		bound, err = strconv.Atoi(arg)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == reflect.TypeFor[time.Duration]() {
+			var d time.Duration
+
+			d, err = time.ParseDuration(arg)
+			bound = int64(d)
+
+			break
+		}
+
+		fallthrough
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		if _, err = strconv.ParseFloat(arg, 64); err == nil {
+			bound = json.Number(arg)
+		}
+	default:
+		return fmt.Errorf("rule %s not supported for %s", rule, t.Kind())
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s rule: %w", rule, err)
+	}
+
+	c[kw] = bound
+
+	return nil
+}
+
+// addPattern adds the pattern constraint to s, next to the existing one, if any.
+func addPattern(s schema, pattern string) {
+	if _, ok := s["pattern"]; !ok {
+		s["pattern"] = pattern
+		return
+	}
+
+	all, _ := s["allOf"].([]schema)
+	s["allOf"] = append(all, schema{"pattern": pattern})
+}
@@ -0,0 +1,202 @@
+package confetti
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// redactedText replaces secret values when printed, logged or marshaled.
+const redactedText = "[REDACTED]"
+
+// Secret holds a sensitive config value (e.g. a password), which is masked whenever it is
+// printed (with any fmt verb), logged (via log/slog) or marshaled to JSON. Use Value to get it.
+//
+// It can be loaded by all the loaders: from JSON like T would and from strings (e.g. env vars)
+// like T would, too, except for custom decoders (see WithDecoder), which are not consulted.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret returns a Secret holding value.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Value returns the secret value.
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+func (Secret[T]) isSecret() {}
+
+func (Secret[T]) String() string {
+	return redactedText
+}
+
+func (Secret[T]) GoString() string {
+	return redactedText
+}
+
+// Format implements fmt.Formatter, so that no verb can print the secret value.
+func (Secret[T]) Format(f fmt.State, _ rune) {
+	io.WriteString(f, redactedText) //nolint:errcheck // ok
+}
+
+func (Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(redactedText)
+}
+
+func (Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedText)
+}
+
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.value)
+}
+
+// copySecret sets the value of s to a deep copy of that of src, which must be a Secret[T] (see deepClone).
+func (s *Secret[T]) copySecret(src any, copyFn func(dst, src reflect.Value)) {
+	v := src.(Secret[T]).value //nolint:forcetypeassert // guaranteed by copyDeep.
+	copyFn(reflect.ValueOf(&s.value).Elem(), reflect.ValueOf(&v).Elem())
+}
+
+// plainJSON returns the JSON encoding of the secret value itself, unlike MarshalJSON (see completeJSON).
+func (s Secret[T]) plainJSON() ([]byte, error) {
+	return json.Marshal(s.value)
+}
+
+// expandString interpolates the secret value, if it is a string (see WithInterpolation).
+func (s *Secret[T]) expandString(fn func(string) (string, error)) error {
+	str, ok := any(s.value).(string)
+	if !ok {
+		return nil
+	}
+
+	str, err := fn(str)
+	if err != nil {
+		return err
+	}
+
+	s.value, _ = any(str).(T)
+
+	return nil
+}
+
+func (s *Secret[T]) UnmarshalText(text []byte) error {
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}
+	return dec.setValue(reflect.ValueOf(&s.value).Elem(), string(text), "secret")
+}
+
+// Redacted returns a copy of cfg (a struct or a pointer to one) with its secret fields masked,
+// fit for printing or logging: strings are replaced with "[REDACTED]" and values of other types
+// are zeroed (Secret fields need no masking). Empty values are left as they are. The original
+// config is never modified.
+//
+// Secret fields are those tagged `secret:"true"` (or nested in a struct tagged so) and, if
+// prov is given, those set by the SSM or Secrets Manager loaders (see WithProvenance).
+func Redacted[T any](cfg T, prov ...Provenance) T {
+	p := Provenance{}
+	for _, pv := range prov {
+		for path, src := range pv {
+			p[path] = src
+		}
+	}
+
+	redact(reflect.ValueOf(&cfg).Elem(), "", false, p)
+
+	return cfg
+}
+
+// nestsFields reports whether the values of type t may hold struct fields (and so secret ones).
+func nestsFields(t reflect.Type) bool {
+	switch t.Kind() { //nolint:exhaustive // ok
+	case reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// redact masks the secret values of v, which is (part of) a copy of the config, copying the
+// pointers and slices on the way, so that the values they point to are not modified.
+func redact(v reflect.Value, path string, secret bool, prov Provenance) {
+	switch v.Kind() { //nolint:exhaustive // ok
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(v.Elem())
+		v.Set(p)
+		redact(p.Elem(), path, secret, prov)
+	case reflect.Slice:
+		if v.IsNil() || (!secret && !nestsFields(v.Type().Elem())) {
+			break
+		}
+
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		v.Set(s)
+
+		for i := range s.Len() {
+			redact(s.Index(i), path, secret, prov)
+		}
+	case reflect.Map:
+		if v.IsNil() || (!secret && !nestsFields(v.Type().Elem())) {
+			break
+		}
+
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+
+		for iter := v.MapRange(); iter.Next(); {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(iter.Value())
+			redact(e, path, secret, prov)
+			m.SetMapIndex(iter.Key(), e)
+		}
+
+		v.Set(m)
+	case reflect.Struct:
+		if _, ok := v.Interface().(interface{ isSecret() }); ok {
+			break
+		}
+
+		if (decoder{}).decodable(v.Type()) {
+			if secret {
+				v.SetZero()
+			}
+
+			break
+		}
+
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !v.Field(i).CanSet() {
+				continue
+			}
+
+			fieldPath := joinPath(path, field.Name)
+			src := prov.Source(fieldPath)
+			fieldSecret := secret || field.Tag.Get("secret") == "true" ||
+				strings.HasPrefix(src, "ssm:") || strings.HasPrefix(src, "secretsmanager:")
+
+			redact(v.Field(i), fieldPath, fieldSecret, prov)
+		}
+	default:
+		if !secret || v.IsZero() {
+			break
+		}
+
+		if v.Kind() == reflect.String {
+			v.SetString(redactedText)
+			break
+		}
+
+		v.SetZero()
+	}
+}
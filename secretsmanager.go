@@ -0,0 +1,65 @@
+package confetti
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// SecretsManagerAPI is the minimal interface for Secrets Manager GetSecretValue used by secretsManagerLoader.
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// secretsManagerLoader loads config from an AWS Secrets Manager secret containing a JSON string.
+type secretsManagerLoader struct {
+	secretID     string
+	awsRegion    string
+	profile      string
+	versionStage string
+}
+
+func (s secretsManagerLoader) Load(config any, opts *Options) (err error) {
+	var svc SecretsManagerAPI
+
+	ctx := opts.context()
+
+	if opts != nil && opts.mockedSecretsManager != nil {
+		svc = opts.mockedSecretsManager
+	} else {
+		var cfg aws.Config
+
+		if cfg, err = loadAWSConfig(ctx, s.awsRegion, s.profile); err != nil {
+			return
+		}
+
+		svc = secretsmanager.NewFromConfig(cfg)
+	}
+
+	input := &secretsmanager.GetSecretValueInput{SecretId: &s.secretID}
+	if s.versionStage != "" {
+		input.VersionStage = &s.versionStage
+	}
+
+	resp, err := svc.GetSecretValue(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", s.secretID, err)
+	}
+
+	switch {
+	case resp.SecretString != nil:
+		return loadJSON(strings.NewReader(*resp.SecretString), config, opts)
+	case resp.SecretBinary != nil:
+		return loadJSON(bytes.NewReader(resp.SecretBinary), config, opts)
+	default:
+		return fmt.Errorf("secret %s not found or has no value", s.secretID)
+	}
+}
+
+func (s secretsManagerLoader) String() string {
+	return "secretsmanager:" + s.secretID
+}
@@ -4,6 +4,9 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,9 +14,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
-// SSMAPI is the minimal interface for SSM GetParameter used by ssmLoader.
+// SSMAPI is the minimal interface for the SSM operations used by the SSM loaders
+// (GetParameter by ssmLoader, GetParametersByPath by ssmPathLoader and GetParameters by ssmRefsLoader).
 type SSMAPI interface {
 	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+	GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
+	GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
 }
 
 // ssmLoader loads config from an AWS SSM parameter containing a JSON string.
@@ -23,33 +29,44 @@ type ssmLoader struct {
 	profile   string
 }
 
-const DefaultAWSRegion = "us-east-1"
+// ssmPathLoader loads config from all the AWS SSM parameters under a path,
+// one parameter per field.
+type ssmPathLoader struct {
+	path      string
+	awsRegion string
+	profile   string
+}
 
-func (s ssmLoader) Load(config any, ownConfig *confetti) (err error) {
-	cfgOpts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cmp.Or(s.awsRegion, DefaultAWSRegion))}
+// ssmRefsLoader loads the fields referencing an AWS SSM parameter via their `ssm` struct tag.
+type ssmRefsLoader struct {
+	awsRegion string
+	profile   string
+}
 
-	if s.profile != "" {
-		cfgOpts = append(cfgOpts, awsconfig.WithSharedConfigProfile(s.profile))
-	}
+// ssmRef is a field referencing an SSM parameter.
+type ssmRef struct {
+	v    reflect.Value
+	path string
+}
 
-	var svc SSMAPI
+const (
+	DefaultAWSRegion = "us-east-1"
 
-	if ownConfig != nil && ownConfig.mockedSSM != nil {
-		svc = ownConfig.mockedSSM
-	} else {
-		var cfg aws.Config
+	// ssmBatchSize is the maximum number of parameters GetParameters accepts.
+	ssmBatchSize = 10
+)
 
-		cfg, err = awsconfig.LoadDefaultConfig(context.Background(), cfgOpts...)
-		if err != nil {
-			return fmt.Errorf("failed to load AWS config: %w", err)
-		}
+func (s ssmLoader) Load(config any, opts *Options) (err error) {
+	ctx := opts.context()
 
-		svc = ssm.NewFromConfig(cfg)
+	svc, err := ssmClient(ctx, opts, s.awsRegion, s.profile)
+	if err != nil {
+		return
 	}
 
 	decrypted := true
 
-	resp, err := svc.GetParameter(context.Background(), &ssm.GetParameterInput{
+	resp, err := svc.GetParameter(ctx, &ssm.GetParameterInput{
 		Name: &s.key, WithDecryption: &decrypted,
 	})
 	if err != nil {
@@ -60,11 +77,310 @@ func (s ssmLoader) Load(config any, ownConfig *confetti) (err error) {
 		return fmt.Errorf("parameter %s not found or has no value", s.key)
 	}
 
-	errOnUnknown := false
+	return loadJSON(strings.NewReader(*resp.Parameter.Value), config, opts)
+}
+
+func (s ssmLoader) String() string {
+	return "ssm:" + s.key
+}
+
+func (s ssmPathLoader) Load(config any, opts *Options) (err error) {
+	ctx := opts.context()
+
+	svc, err := ssmClient(ctx, opts, s.awsRegion, s.profile)
+	if err != nil {
+		return
+	}
+
+	path := "/" + strings.Trim(s.path, "/")
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}.withCustom(opts)
+	unknowns := []string{}
+	recursive, decrypted := true, true
+	input := &ssm.GetParametersByPathInput{Path: &path, Recursive: &recursive, WithDecryption: &decrypted}
+
+	for {
+		var resp *ssm.GetParametersByPathOutput
+
+		resp, err = svc.GetParametersByPath(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to get SSM parameters by path %s: %w", path, err)
+		}
+
+		for _, p := range resp.Parameters {
+			if p.Name == nil || p.Value == nil {
+				continue
+			}
+
+			var ok bool
+
+			rel := strings.TrimPrefix(strings.TrimPrefix(*p.Name, path), "/")
+
+			ok, err = setSSMParam(reflect.ValueOf(config).Elem(), strings.Split(rel, "/"), *p.Value, *p.Name, dec, opts)
+			if err != nil {
+				return
+			}
+
+			if !ok {
+				unknowns = append(unknowns, *p.Name)
+			}
+		}
+
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			break
+		}
+
+		input.NextToken = resp.NextToken
+	}
+
+	if len(unknowns) > 0 && opts != nil && opts.ErrOnUnknown {
+		slices.Sort(unknowns)
+
+		return fmt.Errorf("%w: unknown SSM parameters: %v", ErrUnknownFields, unknowns)
+	}
+
+	return
+}
+
+func (s ssmPathLoader) String() string {
+	return "ssm:" + s.path
+}
+
+func (s ssmRefsLoader) Load(config any, opts *Options) (err error) {
+	dec := decoder{separator: DefaultSeparator, kvSeparator: DefaultKVSeparator}.withCustom(opts)
+	refs := map[string][]ssmRef{}
+
+	collectSSMRefs(reflect.ValueOf(config).Elem(), "", dec, refs)
+
+	if len(refs) == 0 {
+		return
+	}
+
+	ctx := opts.context()
+
+	svc, err := ssmClient(ctx, opts, s.awsRegion, s.profile)
+	if err != nil {
+		return
+	}
+
+	names := slices.Sorted(maps.Keys(refs))
+	values, err := getSSMParams(ctx, svc, names)
+	if err != nil {
+		return
+	}
+
+	for _, name := range names {
+		for _, ref := range refs[name] {
+			if err = dec.setValue(ref.v, values[name], "ssm "+name); err != nil {
+				return
+			}
+
+			opts.MarkSet(ref.path, "ssm:"+name)
+		}
+	}
+
+	return
+}
+
+func (s ssmRefsLoader) String() string {
+	return "ssm:refs"
+}
+
+// collectSSMRefs collects the fields of struct v (and of its nested structs) whose `ssm`
+// struct tag is an absolute parameter name, indexed by that name.
+func collectSSMRefs(v reflect.Value, path string, dec decoder, refs map[string][]ssmRef) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		fieldVal := v.Field(i)
+
+		if !fieldVal.CanSet() {
+			continue
+		}
+
+		fieldPath := joinPath(path, field.Name)
+
+		if name := field.Tag.Get("ssm"); strings.HasPrefix(name, "/") {
+			refs[name] = append(refs[name], ssmRef{v: fieldVal, path: fieldPath})
+			continue
+		}
+
+		if dec.nested(fieldVal.Type()) {
+			if fieldVal.Kind() == reflect.Ptr {
+				if fieldVal.IsNil() {
+					continue
+				}
+
+				fieldVal = fieldVal.Elem()
+			}
+
+			collectSSMRefs(fieldVal, fieldPath, dec, refs)
+		}
+	}
+}
+
+// getSSMParams fetches (and decrypts) the given parameters in batches, returning their values
+// by name. All the parameters that could not be found are reported at once.
+func getSSMParams(ctx context.Context, svc SSMAPI, names []string) (map[string]string, error) {
+	values, missing, err := fetchSSMParams(ctx, svc, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("SSM parameters not found or have no value: %v", missing)
+	}
+
+	return values, nil
+}
+
+// fetchSSMParams is like getSSMParams, but returns the (sorted) names of the parameters that
+// could not be found instead of failing.
+func fetchSSMParams(ctx context.Context, svc SSMAPI, names []string) (values map[string]string, missing []string, err error) {
+	values, decrypted := map[string]string{}, true
+
+	for batch := range slices.Chunk(names, ssmBatchSize) {
+		var resp *ssm.GetParametersOutput
+
+		resp, err = svc.GetParameters(ctx, &ssm.GetParametersInput{Names: batch, WithDecryption: &decrypted})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get SSM parameters %v: %w", batch, err)
+		}
+
+		for _, p := range resp.Parameters {
+			if p.Name != nil && p.Value != nil {
+				values[*p.Name] = *p.Value
+			}
+		}
+
+		missing = append(missing, resp.InvalidParameters...)
+	}
+
+	for _, name := range names {
+		if _, ok := values[name]; !ok && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	slices.Sort(missing)
+
+	return values, missing, nil
+}
+
+// ssmClient returns the mocked SSM client, if any, or a real one for the given region and profile.
+func ssmClient(ctx context.Context, opts *Options, awsRegion, profile string) (SSMAPI, error) {
+	if opts != nil && opts.mockedSSM != nil {
+		return opts.mockedSSM, nil
+	}
+
+	cfg, err := loadAWSConfig(ctx, awsRegion, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssm.NewFromConfig(cfg), nil
+}
+
+// loadAWSConfig loads the default AWS config for the given region (or DefaultAWSRegion) and profile.
+func loadAWSConfig(ctx context.Context, awsRegion, profile string) (aws.Config, error) {
+	cfgOpts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cmp.Or(awsRegion, DefaultAWSRegion))}
+
+	if profile != "" {
+		cfgOpts = append(cfgOpts, awsconfig.WithSharedConfigProfile(profile))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, cfgOpts...)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// setSSMParam sets the field of struct v that the (relative) parameter name segments map to,
+// to val. Each segment is matched against the `ssm` tag of the fields or, failing that, their
+// names, both compared in UPPER_SNAKE_CASE (e.g. "max-retries" matches MaxRetries). Segments
+// traverse nested structs and the last segment under a map field is used as its key.
+// It reports whether a matching field was found.
+func setSSMParam(v reflect.Value, segs []string, val, name string, dec decoder, opts *Options) (bool, error) {
+	index, fieldPath, key, ok := ssmField(v.Type(), segs, dec)
+	if !ok {
+		return false, nil
+	}
+
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(i)
+	}
+
+	var err error
+
+	if key != "" {
+		err = dec.setMapEntry(v, key, val, "ssm "+name)
+	} else {
+		err = dec.setValue(v, val, "ssm "+name)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	opts.MarkSet(fieldPath, "ssm:"+name)
+
+	return true, nil
+}
+
+// ssmField resolves the parameter name segments to a field of struct type t, returning
+// its index sequence, its field path and, if the last segment is a map key, that key.
+func ssmField(t reflect.Type, segs []string, dec decoder) (index []int, path, key string, ok bool) {
+	for i, seg := range segs {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() == reflect.Map && i == len(segs)-1 {
+			return index, path, seg, true
+		}
+
+		if t.Kind() != reflect.Struct || !dec.nested(t) {
+			return nil, "", "", false
+		}
+
+		want := camelToUpperSnake(strings.ReplaceAll(seg, "-", "_"))
+		found := false
+
+		for j := range t.NumField() {
+			field := t.Field(j)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Name
+			if tag := field.Tag.Get("ssm"); tag != "" && !strings.HasPrefix(tag, "/") {
+				name = strings.ReplaceAll(tag, "-", "_")
+			}
+
+			if camelToUpperSnake(name) == want {
+				index, path, t, found = append(index, j), joinPath(path, field.Name), field.Type, true
+				break
+			}
+		}
+
+		if !found {
+			return nil, "", "", false
+		}
+	}
 
-	if ownConfig != nil {
-		errOnUnknown = ownConfig.errOnUnknown
+	if len(segs) == 0 || dec.nested(t) {
+		return nil, "", "", false
 	}
 
-	return loadJSON(strings.NewReader(*resp.Parameter.Value), config, errOnUnknown)
+	return index, path, "", true
 }
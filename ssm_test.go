@@ -0,0 +1,68 @@
+//nolint:testpackage // ok
+package confetti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+type batchSSM struct {
+	SSMAPI
+
+	batches []int
+}
+
+func (b *batchSSM) GetParameters(_ context.Context, params *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	if len(params.Names) > ssmBatchSize {
+		return nil, errors.New("too many parameters")
+	}
+
+	b.batches = append(b.batches, len(params.Names))
+	out := &ssm.GetParametersOutput{}
+
+	for _, name := range params.Names {
+		if name == "/missing" {
+			out.InvalidParameters = append(out.InvalidParameters, name)
+			continue
+		}
+
+		value := "value of " + name
+		out.Parameters = append(out.Parameters, ssmtypes.Parameter{Name: &name, Value: &value})
+	}
+
+	return out, nil
+}
+
+func TestGetSSMParams(t *testing.T) {
+	t.Parallel()
+
+	names := []string{}
+	for i := range 25 {
+		names = append(names, fmt.Sprintf("/param/%02d", i))
+	}
+
+	svc := &batchSSM{}
+
+	values, err := getSSMParams(t.Context(), svc, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(svc.batches) != "[10 10 5]" {
+		t.Errorf("batches = %v; want [10 10 5]", svc.batches)
+	}
+
+	if len(values) != 25 || values["/param/24"] != "value of /param/24" {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	_, err = getSSMParams(t.Context(), svc, append(names, "/missing"))
+	if err == nil || err.Error() != "SSM parameters not found or have no value: [/missing]" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package toml_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexaandru/confetti"
+	"github.com/alexaandru/confetti/toml"
+)
+
+type Config struct {
+	Host      string
+	Port      int           `toml:"port"`
+	Timeout   time.Duration `json:"timeout"`
+	Retry     time.Duration
+	StartedAt time.Time `toml:"started_at"`
+	Tags      []string
+	DB        struct {
+		User     string `toml:"user_name"`
+		Password string `json:"pass"`
+	} `toml:"database"`
+}
+
+var tomlData = `
+host = "localhost"
+port = 8080
+timeout = "5s"
+retry = 1_000_000_000
+started_at = 1979-05-27T07:32:00Z
+tags = ["a", "b"]
+
+[database]
+user_name = "admin"
+pass = "secret"
+`
+
+func ExampleWithTOML() {
+	file := "test_config.toml"
+	if err := os.WriteFile(file, []byte(tomlData), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	cfg := &Config{}
+	if err := confetti.Load(cfg, toml.WithTOML(file)); err != nil {
+		panic("Load failed: " + err.Error())
+	}
+
+	fmt.Printf("%s:%d %s %s %v\n", cfg.Host, cfg.Port, cfg.Timeout, cfg.Retry, cfg.Tags)
+	fmt.Printf("%s/%s %s\n", cfg.DB.User, cfg.DB.Password, cfg.StartedAt.Format(time.RFC3339))
+	// Output:
+	// localhost:8080 5s 1s [a b]
+	// admin/secret 1979-05-27T07:32:00Z
+}
+
+func ExampleWithTOML_sources() {
+	for _, src := range []any{[]byte(tomlData), bytes.NewReader([]byte(tomlData)), strings.NewReader(tomlData)} {
+		cfg := &Config{}
+		if err := confetti.Load(cfg, toml.WithTOML(src)); err != nil {
+			panic("Load failed: " + err.Error())
+		}
+
+		fmt.Println(cfg.Host, cfg.Port)
+	}
+	// Output:
+	// localhost 8080
+	// localhost 8080
+	// localhost 8080
+}
+
+func ExampleWithTOML_local_date() {
+	cfg := &Config{}
+	if err := confetti.Load(cfg, toml.WithTOML([]byte("started_at = 1979-05-27"))); err != nil {
+		panic("Load failed: " + err.Error())
+	}
+
+	fmt.Println(cfg.StartedAt.Format(time.DateOnly))
+	// Output:
+	// 1979-05-27
+}
+
+func ExampleWithTOML_unknown_fields() {
+	data := []byte("host = \"localhost\"\n[database]\nuser_name = \"admin\"\nrole = \"owner\"\n")
+
+	cfg := &Config{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), toml.WithTOML(data))
+
+	fmt.Println(cfg.Host, cfg.DB.User)
+	fmt.Println(errors.Is(err, confetti.ErrUnknownFields), err)
+	// Output:
+	// localhost admin
+	// true unknown fields in config: json: unknown field "role"
+}
+
+func ExampleWithTOML_errors() {
+	cfg := &Config{}
+
+	fmt.Println(confetti.Load(cfg, toml.WithTOML(123)))
+	fmt.Println(confetti.Load(cfg, toml.WithTOML("no_such_file.toml")))
+	fmt.Println(confetti.Load(cfg, toml.WithTOML([]byte("port = "))))
+	// Output:
+	// unsupported type for WithTOML: int
+	// open no_such_file.toml: no such file or directory
+	// toml: line 1 (last key "port"): unexpected EOF; expected value
+}
+
+func ExampleWithTOML_embedded() {
+	type Base struct {
+		LogLevel string `toml:"log_level"`
+	}
+
+	type EmbeddedConfig struct {
+		*Base
+		Host string
+	}
+
+	cfg := &EmbeddedConfig{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), toml.WithTOML([]byte("host = \"localhost\"\nlog_level = \"debug\"\n")))
+
+	fmt.Println(cfg.Host, cfg.LogLevel, err)
+	// Output:
+	// localhost debug <nil>
+}
@@ -0,0 +1,53 @@
+// Package toml provides a confetti loader for TOML sources. It lives in its own module
+// so that only the programs using it depend on a TOML parser.
+package toml
+
+import (
+	bstoml "github.com/BurntSushi/toml"
+	"github.com/alexaandru/confetti"
+	"github.com/alexaandru/confetti/internal/remap"
+)
+
+// tomlLoader loads config from a TOML file, []byte, or io.Reader.
+type tomlLoader struct {
+	src remap.Source
+}
+
+// WithTOML returns a loader that loads the config struct from a TOML source,
+// which can be: a file path (string), []byte, io.ReadSeeker or io.Reader.
+//
+// Keys are matched to fields by their `toml` tag, falling back to their `json` tag and then to
+// their (case-insensitive) Go name. The document is then loaded exactly like a JSON one would be
+// (see confetti.WithJSON), so that confetti.WithErrOnUnknown is honoured. Durations can be
+// given as strings (e.g. "5s") or nanoseconds and datetimes (offset or local ones) map to time.Time.
+func WithTOML(src any) tomlLoader {
+	return tomlLoader{src: remap.NewSource(src, "WithTOML")}
+}
+
+func (t tomlLoader) Load(config any, opts *confetti.Options) (err error) {
+	data, err := t.src.Read()
+	if err != nil {
+		return
+	}
+
+	var doc any
+
+	if err = bstoml.Unmarshal(data, &doc); err != nil || doc == nil {
+		return
+	}
+
+	if data, err = remap.ToJSON(doc, config, "toml"); err != nil {
+		return
+	}
+
+	return confetti.WithJSON(data).Load(config, opts)
+}
+
+func (t tomlLoader) String() string {
+	return "toml:" + t.src.Name
+}
+
+// File returns the path of the file, if loading from one (see confetti.FileLoader).
+func (t tomlLoader) File() string {
+	return t.src.Path()
+}
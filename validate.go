@@ -0,0 +1,257 @@
+package confetti
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validator can be implemented by the config (or any of its nested structs)
+// to validate itself once all loaders ran. Pointer receivers are supported.
+type Validator interface {
+	Validate() error
+}
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// errList is a list of errors, reported on a single line.
+type errList []error
+
+func (e errList) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (e errList) Unwrap() []error {
+	return e
+}
+
+// validate checks struct v against the `validate` struct tags of its fields and calls the
+// Validate method of v and of any nested struct implementing Validator. All the errors are
+// collected and returned at once, wrapped in ErrInvalidConfig.
+func validate(v reflect.Value) error {
+	errs := validateStruct(v, "", true, map[structPtr]bool{{v.Type(), v.Addr().Pointer()}: true})
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", ErrInvalidConfig, errs)
+}
+
+// structPtr identifies a struct by its type and address, which it may share with its first field.
+type structPtr struct {
+	t    reflect.Type
+	addr uintptr
+}
+
+// validateStruct validates struct v (see validate), calling its Validate method only if
+// callValidate is true. The seen structs, which v is nested in, are not descended into again,
+// so that cyclic values are validated only once.
+func validateStruct(v reflect.Value, path string, callValidate bool, seen map[structPtr]bool) (errs errList) {
+	t := v.Type()
+
+	// The Validate methods of embedded structs are promoted to v, if it has none of its own,
+	// so they are not called on their own, to report their errors only once. If v has a Validate
+	// method of its own instead, it is up to it to call theirs.
+	_, isValidator := v.Addr().Interface().(Validator)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		fieldVal := v.Field(i)
+
+		if !fieldVal.CanSet() {
+			continue
+		}
+
+		fieldPath, callFieldValidate := joinPath(path, field.Name), !field.Anonymous || !isValidator
+
+		if fieldVal.Kind() == reflect.Struct {
+			errs = append(errs, validateStruct(fieldVal, fieldPath, callFieldValidate, seen)...)
+		}
+
+		// Nil pointers are considered omitted, others are validated through.
+		if fieldVal.Kind() == reflect.Ptr {
+			if fieldVal.IsNil() {
+				continue
+			}
+
+			if key := (structPtr{fieldVal.Type().Elem(), fieldVal.Pointer()}); key.t.Kind() == reflect.Struct && !seen[key] {
+				seen[key] = true
+				errs = append(errs, validateStruct(fieldVal.Elem(), fieldPath, callFieldValidate, seen)...)
+				delete(seen, key)
+			}
+
+			fieldVal = fieldVal.Elem()
+		}
+
+		if rules, ok := field.Tag.Lookup("validate"); ok {
+			if err := validateField(fieldVal, rules, fieldPath); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if vd, ok := v.Addr().Interface().(Validator); ok && callValidate {
+		if err := vd.Validate(); err != nil {
+			if path != "" {
+				err = fmt.Errorf("%s: %w", path, err)
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	return
+}
+
+// validateField checks v against the comma separated rules. Supported rules are:
+// omitempty (skip the other rules if v is zero), min=N and max=N (the value for numbers and
+// durations, the length for strings, slices and maps), oneof=A B C, url, hostport and
+// regexp=PATTERN, which must be the last rule since the pattern may contain commas.
+//
+// Except for min and max, the rules apply to each element of slices.
+// The name is used as the prefix of the returned error.
+func validateField(v reflect.Value, rules, name string) error {
+	for rules != "" {
+		var rule string
+
+		if strings.HasPrefix(rules, "regexp=") {
+			rule, rules = rules, ""
+		} else {
+			rule, rules, _ = strings.Cut(rules, ",")
+		}
+
+		rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch rule {
+		case "omitempty":
+			if v.IsZero() {
+				return nil
+			}
+		case "min", "max":
+			if err := checkBound(v, rule, arg); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		case "oneof", "url", "hostport", "regexp":
+			if v.Kind() != reflect.Slice {
+				if err := checkString(v, rule, arg); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+
+				continue
+			}
+
+			for j := range v.Len() {
+				if err := checkString(v.Index(j), rule, arg); err != nil {
+					return fmt.Errorf("%s[%d]: %w", name, j, err)
+				}
+			}
+		default:
+			return fmt.Errorf("%s: unknown validation rule %q", name, rule)
+		}
+	}
+
+	return nil
+}
+
+// checkBound checks v against the min or max rule with the given bound.
+func checkBound(v reflect.Value, name, arg string) error {
+	var (
+		val, bound float64
+		what       = "value"
+		err        error
+	)
+
+	switch v.Kind() { //nolint:exhaustive // ok
+	case reflect.String, reflect.Slice, reflect.Map:
+		val, what = float64(v.Len()), "length"
+		bound, err = strconv.ParseFloat(arg, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeFor[time.Duration]() {
+			var d time.Duration
+
+			d, err = time.ParseDuration(arg)
+			val, bound = float64(v.Int()), float64(d)
+
+			break
+		}
+
+		val = float64(v.Int())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val = float64(v.Uint())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case reflect.Float32, reflect.Float64:
+		val = v.Float()
+		bound, err = strconv.ParseFloat(arg, 64)
+	default:
+		return fmt.Errorf("rule %s not supported for %s", name, v.Kind())
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s rule: %w", name, err)
+	}
+
+	if name == "min" && val < bound {
+		return fmt.Errorf("%s must be at least %s", what, arg)
+	}
+
+	if name == "max" && val > bound {
+		return fmt.Errorf("%s must be at most %s", what, arg)
+	}
+
+	return nil
+}
+
+// checkString checks the string v against the oneof, url, hostport or regexp rule.
+func checkString(v reflect.Value, name, arg string) error {
+	if v.Kind() != reflect.String {
+		if name != "oneof" || !v.CanInterface() {
+			return fmt.Errorf("rule %s not supported for %s", name, v.Kind())
+		}
+	}
+
+	s := fmt.Sprint(v.Interface())
+
+	switch name {
+	case "oneof":
+		if !slices.Contains(strings.Fields(arg), s) {
+			return fmt.Errorf("must be one of [%s]", arg)
+		}
+	case "url":
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("must be a valid URL")
+		}
+	case "hostport":
+		_, port, err := net.SplitHostPort(s)
+		if err == nil {
+			_, err = strconv.ParseUint(port, 10, 16)
+		}
+
+		if err != nil {
+			return errors.New("must be a host:port pair")
+		}
+	case "regexp":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return fmt.Errorf("invalid regexp rule: %w", err)
+		}
+
+		if !re.MatchString(s) {
+			return fmt.Errorf("must match %s", arg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+//nolint:testpackage // ok
+package confetti
+
+import (
+	"reflect"
+	"testing"
+)
+
+type validateNode struct {
+	Name string `validate:"min=1"`
+	Next *validateNode
+}
+
+func TestValidateCyclic(t *testing.T) {
+	t.Parallel()
+
+	a, b := &validateNode{Name: "a"}, &validateNode{}
+	a.Next, b.Next = b, a
+
+	want := "invalid config: Next.Name: length must be at least 1"
+	if err := validate(reflect.ValueOf(a).Elem()); err == nil || err.Error() != want {
+		t.Errorf("validate() = %v; want %s", err, want)
+	}
+}
+
+type validateShared struct {
+	A *int
+	B *int `validate:"min=5"`
+	C *validateShared
+}
+
+func TestValidateSharedPointers(t *testing.T) {
+	t.Parallel()
+
+	x := 3
+	v := &validateShared{A: &x, B: &x}
+	v.C = &validateShared{B: v.A}
+
+	want := "invalid config: B: value must be at least 5; C.B: value must be at least 5"
+	if err := validate(reflect.ValueOf(v).Elem()); err == nil || err.Error() != want {
+		t.Errorf("validate() = %v; want %s", err, want)
+	}
+
+	type root struct {
+		A int
+		B *int `validate:"min=5"`
+	}
+
+	r := &root{A: 3}
+	r.B = &r.A
+
+	want = "invalid config: B: value must be at least 5"
+	if err := validate(reflect.ValueOf(r).Elem()); err == nil || err.Error() != want {
+		t.Errorf("validate() = %v; want %s", err, want)
+	}
+}
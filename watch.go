@@ -0,0 +1,356 @@
+package confetti
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPollInterval is how often Watch checks the files it loads from for changes.
+const DefaultPollInterval = time.Second
+
+// FileLoader is implemented by the loaders reading a local file (e.g. WithJSON given a path,
+// WithDotEnv), whose path File returns (or "" if not reading from a file). Watch reloads
+// the config whenever any of these files changes.
+type FileLoader interface {
+	Loader
+	File() string
+}
+
+// Change describes a config reload which changed at least one field.
+type Change[T any] struct {
+	// Old and New are the configs before and after the reload. They must not be modified.
+	Old, New *T
+	// Paths lists the (dot separated) paths of the fields that changed, in field order.
+	Paths []string
+	// Provenance records the sources of the fields of New, if Watch was given WithProvenance.
+	Provenance Provenance
+}
+
+// Watcher holds a config which is reloaded when its sources change (see Watch).
+type Watcher[T any] struct {
+	cur  atomic.Pointer[T]
+	base T
+	ctx  context.Context //nolint:containedctx // it is the lifetime of the watcher.
+	lds  []Loader        // the loaders of the reloads, without WithProvenance.
+	prov bool            // whether to record the provenance of the reloads.
+
+	mu     sync.Mutex // guards the fields below and serializes reloads.
+	subs   []func(Change[T])
+	err    error
+	hashes map[string][sha256.Size]byte
+
+	notifyMu sync.Mutex // keeps the notifications in the order of the reloads.
+}
+
+type optsWatchLoader struct {
+	poll, reload time.Duration
+}
+
+// Load is a no-op: the watch options only matter to Watch.
+func (optsWatchLoader) Load(_ any, _ *Options) (err error) {
+	return
+}
+
+// WithPollInterval sets how often Watch checks the loaded files for changes (default is DefaultPollInterval).
+func WithPollInterval(d time.Duration) optsWatchLoader {
+	return optsWatchLoader{poll: d}
+}
+
+// WithReloadInterval makes Watch reload the config every d, whether its files changed or not,
+// which is meant for remote sources (e.g. WithSSM) that cannot be polled for changes.
+func WithReloadInterval(d time.Duration) optsWatchLoader {
+	return optsWatchLoader{reload: d}
+}
+
+// Watch loads cfg, like LoadContext does, then keeps reloading it in the background until ctx is
+// done: whenever any of the files loaded from (see FileLoader) changes, and every reload interval
+// (see WithPollInterval and WithReloadInterval). Each reload loads a fresh copy of cfg, as it was
+// before the initial load, and, if that succeeds (including the required fields check and the
+// validation), atomically swaps it in and notifies the subscribers of the fields that changed.
+// Failed reloads keep the previous config (see Watcher.Err).
+//
+// Only the initial load modifies cfg. Use Watcher.Config to get the current config. Reloads start
+// from a deep copy of cfg, so the maps, slices and pointers it holds are never shared between configs.
+// Likewise, WithProvenance only records the initial load: the provenance of the reloads is reported
+// by Change.Provenance instead.
+func Watch[T any](ctx context.Context, cfg *T, ld Loader, opts ...Loader) (w *Watcher[T], err error) {
+	w = &Watcher[T]{base: deepClone(cfg), ctx: ctx, hashes: map[string][sha256.Size]byte{}}
+	poll, reload := DefaultPollInterval, time.Duration(0)
+
+	for _, ld := range append([]Loader{ld}, opts...) {
+		if _, ok := ld.(optsProvenanceLoader); ok {
+			w.prov = true
+			continue
+		}
+
+		w.lds = append(w.lds, ld)
+
+		if o, ok := ld.(optsWatchLoader); ok {
+			poll, reload = cmp.Or(o.poll, poll), cmp.Or(o.reload, reload)
+		}
+
+		if fl, ok := ld.(FileLoader); ok && fl.File() != "" {
+			w.hashes[fl.File()] = hashFile(fl.File())
+		}
+	}
+
+	if err = LoadContext(ctx, cfg, ld, opts...); err != nil {
+		return nil, err
+	}
+
+	w.cur.Store(cfg)
+
+	go w.run(poll, reload)
+
+	return
+}
+
+// Config returns the current config, which must not be modified.
+func (w *Watcher[T]) Config() *T {
+	return w.cur.Load()
+}
+
+// Subscribe registers fn to be called after each reload which changed the config,
+// from the goroutine which reloaded it. It must not call Reload.
+func (w *Watcher[T]) Subscribe(fn func(Change[T])) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subs = append(w.subs, fn)
+}
+
+// Err returns the error of the last reload, if it failed, or nil.
+func (w *Watcher[T]) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// Reload reloads the config right away, as Watch does when its sources change.
+func (w *Watcher[T]) Reload() error {
+	return w.reload(false)
+}
+
+func (w *Watcher[T]) run(poll, reload time.Duration) {
+	pollTick := time.NewTicker(poll)
+	defer pollTick.Stop()
+
+	var reloadTick <-chan time.Time
+
+	if reload > 0 {
+		t := time.NewTicker(reload)
+		defer t.Stop()
+
+		reloadTick = t.C
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-pollTick.C:
+			w.reload(true) //nolint:errcheck // it is exposed via Err.
+		case <-reloadTick:
+			w.reload(false) //nolint:errcheck // it is exposed via Err.
+		}
+	}
+}
+
+// filesChanged reports whether any of the files changed since last checked.
+// It must be called with w.mu held.
+func (w *Watcher[T]) filesChanged() (changed bool) {
+	for path, h := range w.hashes {
+		if nh := hashFile(path); nh != h {
+			w.hashes[path], changed = nh, true
+		}
+	}
+
+	return
+}
+
+// reload loads a fresh config (only if any of the files changed, if filesOnly is true) and,
+// if successful, swaps it in and notifies the subscribers of the changes, if any.
+func (w *Watcher[T]) reload(filesOnly bool) (err error) {
+	w.mu.Lock()
+
+	if filesOnly && !w.filesChanged() {
+		w.mu.Unlock()
+		return
+	}
+
+	next, lds := deepClone(&w.base), w.lds
+
+	var prov Provenance
+
+	if w.prov {
+		lds = append(slices.Clone(lds), WithProvenance(&prov))
+	}
+
+	if w.err = load(w.ctx, &next, lds); w.err != nil {
+		err = w.err
+		w.mu.Unlock()
+
+		return
+	}
+
+	prev, subs := w.cur.Swap(&next), slices.Clone(w.subs)
+
+	w.notifyMu.Lock()
+	defer w.notifyMu.Unlock()
+
+	w.mu.Unlock()
+
+	if paths := diffPaths(reflect.ValueOf(prev).Elem(), reflect.ValueOf(&next).Elem(), ""); len(paths) > 0 {
+		for _, fn := range subs {
+			fn(Change[T]{Old: prev, New: &next, Paths: paths, Provenance: prov})
+		}
+	}
+
+	return
+}
+
+// hashFile returns the hash of the content of the file at path (the zero hash, if it cannot be read).
+func hashFile(path string) (h [sha256.Size]byte) {
+	data, err := os.ReadFile(path) //nolint:gosec // this is the whole point of the library.
+	if err != nil {
+		return
+	}
+
+	return sha256.Sum256(data)
+}
+
+// diffPaths returns the paths of the fields that differ between structs a and b,
+// descending into nested structs (and pointers to structs, when neither is nil).
+func diffPaths(a, b reflect.Value, path string) (paths []string) {
+	dec := decoder{}
+
+	for i := range a.NumField() {
+		field := a.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath, av, bv := joinPath(path, field.Name), a.Field(i), b.Field(i)
+
+		if dec.nested(field.Type) {
+			if av.Kind() == reflect.Ptr {
+				if av.IsNil() || bv.IsNil() {
+					if av.IsNil() != bv.IsNil() {
+						paths = append(paths, fieldPath)
+					}
+
+					continue
+				}
+
+				av, bv = av.Elem(), bv.Elem()
+			}
+
+			paths = append(paths, diffPaths(av, bv, fieldPath)...)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			paths = append(paths, fieldPath)
+		}
+	}
+
+	return
+}
+
+// deepClone returns a copy of *v which shares no memory with it: pointers, slices, maps and
+// interfaces are copied recursively (as well as the value of Secret fields).
+func deepClone[T any](v *T) (c T) {
+	copyDeep(reflect.ValueOf(&c).Elem(), reflect.ValueOf(v).Elem(), map[structPtr]reflect.Value{})
+	return
+}
+
+// secretCopier is implemented by Secret, whose value copyDeep cannot otherwise reach.
+type secretCopier interface {
+	copySecret(src any, copyFn func(dst, src reflect.Value))
+}
+
+// copyDeep sets dst to a deep copy of src (see deepClone). The seen pointers map to their copies,
+// so that pointer cycles are preserved rather than followed forever. They are keyed by type too,
+// as a pointer to a struct and one to its first field share the same address.
+func copyDeep(dst, src reflect.Value, seen map[structPtr]reflect.Value) {
+	copyFn := func(dst, src reflect.Value) { copyDeep(dst, src, seen) }
+
+	switch src.Kind() { //nolint:exhaustive // ok
+	case reflect.Ptr:
+		if src.IsNil() {
+			break
+		}
+
+		key := structPtr{src.Type().Elem(), src.Pointer()}
+		if p, ok := seen[key]; ok {
+			dst.Set(p)
+			break
+		}
+
+		p := reflect.New(src.Type().Elem())
+		seen[key] = p
+		copyDeep(p.Elem(), src.Elem(), seen)
+		dst.Set(p)
+	case reflect.Slice:
+		if src.IsNil() {
+			break
+		}
+
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			copyDeep(s.Index(i), src.Index(i), seen)
+		}
+
+		dst.Set(s)
+	case reflect.Map:
+		if src.IsNil() {
+			break
+		}
+
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+
+		for iter := src.MapRange(); iter.Next(); {
+			e := reflect.New(src.Type().Elem()).Elem()
+			copyDeep(e, iter.Value(), seen)
+			m.SetMapIndex(iter.Key(), e)
+		}
+
+		dst.Set(m)
+	case reflect.Array:
+		for i := range src.Len() {
+			copyDeep(dst.Index(i), src.Index(i), seen)
+		}
+	case reflect.Interface:
+		if src.IsNil() {
+			break
+		}
+
+		e := reflect.New(src.Elem().Type()).Elem()
+		copyDeep(e, src.Elem(), seen)
+		dst.Set(e)
+	case reflect.Struct:
+		dst.Set(src)
+
+		if sc, ok := dst.Addr().Interface().(secretCopier); ok {
+			sc.copySecret(src.Interface(), copyFn)
+			break
+		}
+
+		for i := range dst.NumField() {
+			if dst.Field(i).CanSet() {
+				copyDeep(dst.Field(i), src.Field(i), seen)
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
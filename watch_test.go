@@ -0,0 +1,27 @@
+//nolint:testpackage // ok
+package confetti
+
+import "testing"
+
+type cloneNode struct {
+	A    int
+	Next *cloneNode
+}
+
+func TestDeepCloneSharedAddress(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		N *cloneNode
+		P *int
+	}
+
+	n := &cloneNode{A: 1}
+	n.Next = n
+	cfg := &config{N: n, P: &n.A}
+
+	c := deepClone(cfg)
+	if c.N == n || c.P == cfg.P || c.N.Next != c.N || c.N.A != 1 || *c.P != 1 {
+		t.Errorf("deepClone() = %+v; want a deep copy of %+v", c, cfg)
+	}
+}
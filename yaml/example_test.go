@@ -0,0 +1,114 @@
+package yaml_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexaandru/confetti"
+	"github.com/alexaandru/confetti/yaml"
+)
+
+type Config struct {
+	Host    string
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `json:"timeout"`
+	Tags    []string
+	DB      struct {
+		User     string `yaml:"user_name"`
+		Password string `json:"pass"`
+	} `yaml:"database"`
+	Limits map[string]int
+}
+
+var yamlData = `
+host: localhost
+port: 8080
+timeout: 5s
+tags: [a, b]
+database:
+  user_name: admin
+  pass: secret
+limits:
+  cpu: 2
+`
+
+func ExampleWithYAML() {
+	file := "test_config.yaml"
+	if err := os.WriteFile(file, []byte(yamlData), 0o644); err != nil {
+		panic("failed to write test file: " + err.Error())
+	}
+	defer os.Remove(file)
+
+	cfg := &Config{}
+	if err := confetti.Load(cfg, yaml.WithYAML(file)); err != nil {
+		panic("Load failed: " + err.Error())
+	}
+
+	fmt.Printf("%s:%d %s %v\n", cfg.Host, cfg.Port, cfg.Timeout, cfg.Tags)
+	fmt.Printf("%s/%s %v\n", cfg.DB.User, cfg.DB.Password, cfg.Limits)
+	// Output:
+	// localhost:8080 5s [a b]
+	// admin/secret map[cpu:2]
+}
+
+func ExampleWithYAML_sources() {
+	for _, src := range []any{[]byte(yamlData), bytes.NewReader([]byte(yamlData)), strings.NewReader(yamlData)} {
+		cfg := &Config{}
+		if err := confetti.Load(cfg, yaml.WithYAML(src)); err != nil {
+			panic("Load failed: " + err.Error())
+		}
+
+		fmt.Println(cfg.Host, cfg.Port)
+	}
+	// Output:
+	// localhost 8080
+	// localhost 8080
+	// localhost 8080
+}
+
+func ExampleWithYAML_unknown_fields() {
+	data := []byte("host: localhost\ndatabase:\n  user_name: admin\n  role: owner\n")
+
+	cfg := &Config{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), yaml.WithYAML(data))
+
+	fmt.Println(cfg.Host, cfg.DB.User)
+	fmt.Println(errors.Is(err, confetti.ErrUnknownFields), err)
+	// Output:
+	// localhost admin
+	// true unknown fields in config: json: unknown field "role"
+}
+
+func ExampleWithYAML_errors() {
+	cfg := &Config{}
+
+	fmt.Println(confetti.Load(cfg, yaml.WithYAML(123)))
+	fmt.Println(confetti.Load(cfg, yaml.WithYAML("no_such_file.yaml")))
+	fmt.Println(confetti.Load(cfg, yaml.WithYAML([]byte("port: [1"))))
+	// Output:
+	// unsupported type for WithYAML: int
+	// open no_such_file.yaml: no such file or directory
+	// yaml: line 1: did not find expected ',' or ']'
+}
+
+func ExampleWithYAML_embedded() {
+	type Base struct {
+		LogLevel string `yaml:"log_level"`
+	}
+
+	type EmbeddedConfig struct {
+		*Base
+		Host string
+	}
+
+	cfg := &EmbeddedConfig{}
+	err := confetti.Load(cfg, confetti.WithErrOnUnknown(), yaml.WithYAML([]byte("host: localhost\nlog_level: debug\n")))
+
+	fmt.Println(cfg.Host, cfg.LogLevel, err)
+	// Output:
+	// localhost debug <nil>
+}
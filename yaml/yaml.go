@@ -0,0 +1,53 @@
+// Package yaml provides a confetti loader for YAML sources. It lives in its own module
+// so that only the programs using it depend on a YAML parser.
+package yaml
+
+import (
+	"github.com/alexaandru/confetti"
+	"github.com/alexaandru/confetti/internal/remap"
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+// yamlLoader loads config from a YAML file, []byte, or io.Reader.
+type yamlLoader struct {
+	src remap.Source
+}
+
+// WithYAML returns a loader that loads the config struct from a YAML source,
+// which can be: a file path (string), []byte, io.ReadSeeker or io.Reader.
+//
+// Keys are matched to fields by their `yaml` tag, falling back to their `json` tag and then to
+// their (case-insensitive) Go name. The document is then loaded exactly like a JSON one would be
+// (see confetti.WithJSON), so that confetti.WithErrOnUnknown is honoured. Durations can be
+// given as strings (e.g. "5s") or nanoseconds.
+func WithYAML(src any) yamlLoader {
+	return yamlLoader{src: remap.NewSource(src, "WithYAML")}
+}
+
+func (y yamlLoader) Load(config any, opts *confetti.Options) (err error) {
+	data, err := y.src.Read()
+	if err != nil {
+		return
+	}
+
+	var doc any
+
+	if err = goyaml.Unmarshal(data, &doc); err != nil || doc == nil {
+		return
+	}
+
+	if data, err = remap.ToJSON(doc, config, "yaml"); err != nil {
+		return
+	}
+
+	return confetti.WithJSON(data).Load(config, opts)
+}
+
+func (y yamlLoader) String() string {
+	return "yaml:" + y.src.Name
+}
+
+// File returns the path of the file, if loading from one (see confetti.FileLoader).
+func (y yamlLoader) File() string {
+	return y.src.Path()
+}